@@ -165,11 +165,25 @@ func (c consts) Bnot(a I) I {
 }
 
 func (c consts) Lshift(a, s I) (I, xtruth.T) {
-	panic("unimplemented")
+	pa, ps := a.(*C).p, s.(*C).p
+	if ps != nil && *ps < 0 {
+		return c.Zero(), xtruth.False
+	}
+	if pa == nil || ps == nil {
+		return c.Var(), xtruth.X
+	}
+	return c.FromInt64(*pa << uint64(*ps)), xtruth.True
 }
 
 func (c consts) Rshift(a, s I) (I, xtruth.T) {
-	panic("unimplemented")
+	pa, ps := a.(*C).p, s.(*C).p
+	if ps != nil && *ps < 0 {
+		return c.Zero(), xtruth.False
+	}
+	if pa == nil || ps == nil {
+		return c.Var(), xtruth.X
+	}
+	return c.FromInt64(*pa >> uint64(*ps)), xtruth.True
 }
 
 func (c consts) Less(a, b I) xtruth.T {
@@ -194,6 +208,24 @@ func (c consts) Equal(a, b I) xtruth.T {
 	return xtruth.False
 }
 
+func (c consts) Join(a, b I) I {
+	if c.Equal(a, b) == xtruth.True {
+		return a
+	}
+	return c.Var()
+}
+
+func (c consts) Widen(a, b I) I {
+	return c.Join(a, b)
+}
+
+func (c consts) Bound(a, lo, hi I) I {
+	if lo != nil && hi != nil && c.Equal(lo, hi) == xtruth.True {
+		return lo
+	}
+	return a
+}
+
 func (c consts) PlainEncode(w io.Writer) error {
 	return nil
 }
@@ -53,3 +53,14 @@ func TestConstVals(t *testing.T) {
 		t.Errorf("%s == %s => %s\n", v, idx.FromInt64(32), x)
 	}
 }
+
+func TestConstValsBound(t *testing.T) {
+	idx := ConstVals()
+	w := idx.Var()
+	if b := idx.Bound(w, idx.FromInt64(3), idx.FromInt64(3)); idx.Equal(b, idx.FromInt64(3)) != xtruth.True {
+		t.Errorf("bound %s in [3, 3] = %s", w, b)
+	}
+	if b := idx.Bound(w, idx.Zero(), idx.FromInt64(3)); !idx.IsVar(b) {
+		t.Errorf("bound %s in [0, 3] = %s", w, b)
+	}
+}
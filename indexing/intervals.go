@@ -0,0 +1,403 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexing
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/go-air/pal/internal/plain"
+	"github.com/go-air/pal/xtruth"
+)
+
+type intervals struct{}
+
+// Interval is the index type of the Intervals domain.  It represents
+// the int64 values v such that lo <= v <= hi.  The full range of int64
+// is the variable (unknown) value.
+type Interval struct{ lo, hi int64 }
+
+// Intervals returns an indexing.T whose values are ranges [lo, hi] of
+// int64.  Operations which may overflow give the full range, as do
+// operations whose result is not representable as a range.
+func Intervals() T {
+	return intervals{}
+}
+
+// Bounds returns the bounds of v, which must be an interval.
+func Bounds(v I) (lo, hi int64) {
+	iv := v.(*Interval)
+	return iv.lo, iv.hi
+}
+
+func (iv *Interval) Gen() I {
+	return &Interval{}
+}
+
+func (iv *Interval) isVar() bool {
+	return iv.lo == math.MinInt64 && iv.hi == math.MaxInt64
+}
+
+func (iv *Interval) PlainEncode(w io.Writer) error {
+	if iv.isVar() {
+		return plain.Put(w, ".")
+	}
+	err := plain.Put(w, "i")
+	if err != nil {
+		return err
+	}
+	err = plain.EncodeInt64(w, iv.lo)
+	if err != nil {
+		return err
+	}
+	return plain.EncodeInt64(w, iv.hi)
+}
+
+func (iv *Interval) PlainDecode(r io.Reader) error {
+	var buf [1]byte
+	_, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return err
+	}
+	if buf[0] == byte('.') {
+		iv.lo, iv.hi = math.MinInt64, math.MaxInt64
+		return nil
+	}
+	if buf[0] != byte('i') {
+		return fmt.Errorf("unexpected %c != i", buf[0])
+	}
+	lo, err := plain.DecodeInt64From(r)
+	if err != nil {
+		return err
+	}
+	hi, err := plain.DecodeInt64From(r)
+	if err != nil {
+		return err
+	}
+	if lo > hi {
+		return fmt.Errorf("empty interval [%d, %d]", lo, hi)
+	}
+	iv.lo, iv.hi = lo, hi
+	return nil
+}
+
+func (d intervals) Zero() I {
+	return &Interval{0, 0}
+}
+
+func (d intervals) One() I {
+	return &Interval{1, 1}
+}
+
+func (d intervals) IsVar(v I) bool {
+	return v.(*Interval).isVar()
+}
+
+func (d intervals) Var() I {
+	return &Interval{math.MinInt64, math.MaxInt64}
+}
+
+func (d intervals) FromInt64(v int64) I {
+	return &Interval{v, v}
+}
+
+func (d intervals) ToInt64(v I) (int64, bool) {
+	iv := v.(*Interval)
+	if iv.lo != iv.hi {
+		return 0, false
+	}
+	return iv.lo, true
+}
+
+// hull gives the smallest interval containing vs, which
+// must be non-empty.
+func hull(vs ...int64) *Interval {
+	res := &Interval{vs[0], vs[0]}
+	for _, v := range vs[1:] {
+		if v < res.lo {
+			res.lo = v
+		}
+		if v > res.hi {
+			res.hi = v
+		}
+	}
+	return res
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func addOK(a, b int64) (int64, bool) {
+	c := a + b
+	return c, (c > a) == (b > 0)
+}
+
+func mulOK(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return c, false
+	}
+	return c, c/b == a
+}
+
+func (d intervals) Plus(a, b I) I {
+	x, y := a.(*Interval), b.(*Interval)
+	lo, lok := addOK(x.lo, y.lo)
+	hi, hok := addOK(x.hi, y.hi)
+	if !lok || !hok {
+		return d.Var()
+	}
+	return &Interval{lo, hi}
+}
+
+func (d intervals) Times(a, b I) I {
+	x, y := a.(*Interval), b.(*Interval)
+	var cs [4]int64
+	var ok bool
+	for i, p := range [4][2]int64{{x.lo, y.lo}, {x.lo, y.hi}, {x.hi, y.lo}, {x.hi, y.hi}} {
+		cs[i], ok = mulOK(p[0], p[1])
+		if !ok {
+			return d.Var()
+		}
+	}
+	return hull(cs[:]...)
+}
+
+// nonZero splits y into its negative and positive parts.
+func nonZero(y *Interval) (neg, pos *Interval) {
+	if y.lo < 0 {
+		neg = &Interval{y.lo, -1}
+		if y.hi < 0 {
+			neg.hi = y.hi
+		}
+	}
+	if y.hi > 0 {
+		pos = &Interval{1, y.hi}
+		if y.lo > 0 {
+			pos.lo = y.lo
+		}
+	}
+	return
+}
+
+// divisor returns whether the interval y is defined as a divisor.
+func divisor(y *Interval) xtruth.T {
+	switch {
+	case y.lo == 0 && y.hi == 0:
+		return xtruth.False
+	case y.lo <= 0 && y.hi >= 0:
+		return xtruth.X
+	}
+	return xtruth.True
+}
+
+func (d intervals) Div(a, b I) (I, xtruth.T) {
+	x, y := a.(*Interval), b.(*Interval)
+	t := divisor(y)
+	if t == xtruth.False {
+		return d.Zero(), t
+	}
+	var cs []int64
+	neg, pos := nonZero(y)
+	for _, z := range [2]*Interval{neg, pos} {
+		if z == nil {
+			continue
+		}
+		if x.lo == math.MinInt64 && z.hi == -1 {
+			// MinInt64 / -1 overflows
+			return d.Var(), t
+		}
+		// truncated division is monotonic in each argument
+		// on either side of 0.
+		cs = append(cs, x.lo/z.lo, x.lo/z.hi, x.hi/z.lo, x.hi/z.hi)
+	}
+	return hull(cs...), t
+}
+
+func (d intervals) Rem(a, b I) (I, xtruth.T) {
+	x, y := a.(*Interval), b.(*Interval)
+	t := divisor(y)
+	if t == xtruth.False {
+		return d.Zero(), t
+	}
+	// |x % y| < |y| and x % y has the sign of x.
+	m := int64(math.MaxInt64)
+	if y.lo != math.MinInt64 {
+		m = -y.lo
+		if y.hi > m {
+			m = y.hi
+		}
+		m--
+	}
+	res := &Interval{-m, m}
+	if lo := min64(x.lo, 0); lo > res.lo {
+		res.lo = lo
+	}
+	if hi := max64(x.hi, 0); hi < res.hi {
+		res.hi = hi
+	}
+	return res, t
+}
+
+func (d intervals) Band(a, b I) I {
+	x, y := a.(*Interval), b.(*Interval)
+	switch {
+	case x.lo == x.hi && y.lo == y.hi:
+		return d.FromInt64(x.lo & y.lo)
+	case x.lo >= 0 && y.lo >= 0:
+		return &Interval{0, min64(x.hi, y.hi)}
+	case x.lo >= 0:
+		return &Interval{0, x.hi}
+	case y.lo >= 0:
+		return &Interval{0, y.hi}
+	}
+	return d.Var()
+}
+
+func (d intervals) Bnot(a I) I {
+	x := a.(*Interval)
+	return &Interval{^x.hi, ^x.lo}
+}
+
+// shift returns the part of s which is a valid shift count and whether
+// s is valid.
+func shift(s *Interval) (*Interval, xtruth.T) {
+	switch {
+	case s.hi < 0:
+		return nil, xtruth.False
+	case s.lo < 0:
+		return &Interval{0, s.hi}, xtruth.X
+	}
+	return s, xtruth.True
+}
+
+func (d intervals) Lshift(a, s I) (I, xtruth.T) {
+	x := a.(*Interval)
+	z, t := shift(s.(*Interval))
+	if t == xtruth.False {
+		return d.Zero(), t
+	}
+	if x.lo == 0 && x.hi == 0 {
+		return d.Zero(), t
+	}
+	if z.hi >= 63 {
+		return d.Var(), t
+	}
+	var cs [4]int64
+	for i, p := range [4][2]int64{{x.lo, z.lo}, {x.lo, z.hi}, {x.hi, z.lo}, {x.hi, z.hi}} {
+		c := p[0] << uint(p[1])
+		if c>>uint(p[1]) != p[0] {
+			return d.Var(), t
+		}
+		cs[i] = c
+	}
+	return hull(cs[:]...), t
+}
+
+func (d intervals) Rshift(a, s I) (I, xtruth.T) {
+	x := a.(*Interval)
+	z, t := shift(s.(*Interval))
+	if t == xtruth.False {
+		return d.Zero(), t
+	}
+	// x >> k is the same for all k >= 63
+	lo, hi := uint(z.lo), uint(z.hi)
+	if z.lo > 63 {
+		lo = 63
+	}
+	if z.hi > 63 {
+		hi = 63
+	}
+	return hull(x.lo>>lo, x.lo>>hi, x.hi>>lo, x.hi>>hi), t
+}
+
+func (d intervals) Less(a, b I) xtruth.T {
+	x, y := a.(*Interval), b.(*Interval)
+	switch {
+	case x.hi < y.lo:
+		return xtruth.True
+	case x.lo >= y.hi:
+		return xtruth.False
+	}
+	return xtruth.X
+}
+
+func (d intervals) Equal(a, b I) xtruth.T {
+	x, y := a.(*Interval), b.(*Interval)
+	switch {
+	case x.hi < y.lo || y.hi < x.lo:
+		return xtruth.False
+	case x.lo == x.hi && *x == *y:
+		return xtruth.True
+	}
+	return xtruth.X
+}
+
+func (d intervals) Join(a, b I) I {
+	x, y := a.(*Interval), b.(*Interval)
+	return hull(x.lo, x.hi, y.lo, y.hi)
+}
+
+func (d intervals) Widen(a, b I) I {
+	x, y := a.(*Interval), b.(*Interval)
+	res := &Interval{x.lo, x.hi}
+	if y.lo < x.lo {
+		res.lo = math.MinInt64
+	}
+	if y.hi > x.hi {
+		res.hi = math.MaxInt64
+	}
+	return res
+}
+
+// Bound gives a if the bounds exclude all its values, as they may
+// on paths which are not feasible.
+func (d intervals) Bound(a, lo, hi I) I {
+	x := a.(*Interval)
+	res := &Interval{x.lo, x.hi}
+	if lo != nil {
+		res.lo = max64(res.lo, lo.(*Interval).lo)
+	}
+	if hi != nil {
+		res.hi = min64(res.hi, hi.(*Interval).hi)
+	}
+	if res.lo > res.hi {
+		return a
+	}
+	return res
+}
+
+func (d intervals) PlainEncode(w io.Writer) error {
+	return nil
+}
+
+func (d intervals) PlainDecode(r io.Reader) error {
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-air/pal/internal/plain"
+	"github.com/go-air/pal/xtruth"
+)
+
+func TestIntervalsCoding(t *testing.T) {
+	idx := Intervals()
+	for _, i := range [...]I{idx.Zero(), idx.One(), idx.FromInt64(-32), idx.Var(),
+		&Interval{-3, 17}, &Interval{math.MinInt64, 0}} {
+		err := plain.TestRoundTripClobber(i, func(c plain.Coder) {
+			*c.(*Interval) = Interval{}
+		}, false)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestIntervalsBand(t *testing.T) {
+	idx := Intervals()
+	r := idx.Band(idx.Var(), idx.FromInt64(3))
+	if lo, hi := Bounds(r); lo != 0 || hi != 3 {
+		t.Errorf("v&3 = %s", plain.String(r))
+	}
+	if idx.Less(r, idx.FromInt64(4)) != xtruth.True {
+		t.Errorf("v&3 < 4 not true")
+	}
+}
+
+func TestIntervalsWiden(t *testing.T) {
+	idx := Intervals()
+	v := idx.Widen(idx.Zero(), &Interval{0, 1})
+	if lo, hi := Bounds(v); lo != 0 || hi != math.MaxInt64 {
+		t.Errorf("widen: %s", plain.String(v))
+	}
+	w := idx.Widen(v, &Interval{0, 5})
+	if lo, hi := Bounds(w); lo != 0 || hi != math.MaxInt64 {
+		t.Errorf("widen: not stable %s", plain.String(w))
+	}
+}
+
+func TestIntervalsBound(t *testing.T) {
+	idx := Intervals()
+	b := idx.Bound(idx.Var(), idx.Zero(), &Interval{2, 5})
+	if lo, hi := Bounds(b); lo != 0 || hi != 5 {
+		t.Errorf("bound: %s", plain.String(b))
+	}
+	b = idx.Bound(b, &Interval{1, 9}, nil)
+	if lo, hi := Bounds(b); lo != 1 || hi != 5 {
+		t.Errorf("bound: %s", plain.String(b))
+	}
+	a := &Interval{0, 3}
+	if b := idx.Bound(a, idx.FromInt64(4), nil); b != a {
+		t.Errorf("bound: %s", plain.String(b))
+	}
+}
+
+// TestIntervalsSound checks the operations against all values in
+// small intervals.
+func TestIntervalsSound(t *testing.T) {
+	idx := Intervals()
+	var ivs []*Interval
+	for lo := int64(-5); lo <= 5; lo++ {
+		for hi := lo; hi <= 5; hi++ {
+			ivs = append(ivs, &Interval{lo, hi})
+		}
+	}
+	ivs = append(ivs, &Interval{math.MaxInt64 - 2, math.MaxInt64})
+	ivs = append(ivs, &Interval{math.MinInt64, math.MinInt64 + 2})
+	in := func(v int64, r I) bool {
+		lo, hi := Bounds(r)
+		return lo <= v && v <= hi
+	}
+	has := func(t xtruth.T, b bool) bool {
+		return t == xtruth.X || (t == xtruth.True) == b
+	}
+	for _, a := range ivs {
+		for _, b := range ivs {
+			sum, prod := idx.Plus(a, b), idx.Times(a, b)
+			quo, qt := idx.Div(a, b)
+			rem, rt := idx.Rem(a, b)
+			and := idx.Band(a, b)
+			shl, lt := idx.Lshift(a, b)
+			shr, _ := idx.Rshift(a, b)
+			less, eq := idx.Less(a, b), idx.Equal(a, b)
+			join := idx.Join(a, b)
+			for x := a.lo; ; x++ {
+				if !in(^x, idx.Bnot(a)) || !in(x, join) {
+					t.Fatalf("%d not in ^/join %v %v", x, a, b)
+				}
+				for y := b.lo; ; y++ {
+					if !in(x+y, sum) || !in(x*y, prod) || !in(x&y, and) {
+						t.Fatalf("%d, %d not in +*& %v %v", x, y, a, b)
+					}
+					if !has(less, x < y) || !has(eq, x == y) {
+						t.Fatalf("%d, %d: bad cmp %v %v", x, y, a, b)
+					}
+					if !has(qt, y != 0) || !has(rt, y != 0) || !has(lt, y >= 0) {
+						t.Fatalf("%d, %d: bad defined %v %v", x, y, a, b)
+					}
+					if y != 0 && (!in(x/y, quo) || !in(x%y, rem)) {
+						t.Fatalf("%d, %d not in /%% %v %v", x, y, a, b)
+					}
+					if y >= 0 && (!in(x<<uint(y), shl) || !in(x>>uint(y), shr)) {
+						t.Fatalf("%d, %d not in shifts %v %v", x, y, a, b)
+					}
+					if y == b.hi {
+						break
+					}
+				}
+				if x == a.hi {
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,393 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexing
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-air/pal/internal/plain"
+	"github.com/go-air/pal/xtruth"
+)
+
+type symbolic struct {
+	scope string
+	n     *uint64
+}
+
+// Expr is the index type of the Symbolic domain.  It represents
+// the linear expression c + k1*x1 + k2*x2 + ... over symbolic
+// variables xi, together with an interval bounding its value.
+//
+// The arithmetic of expressions wraps like that of int64, so
+// a difference of expressions which is a constant decides
+// equality exactly.
+type Expr struct {
+	c     int64
+	terms []term // sorted by name, with non-zero k
+	rng   Interval
+}
+
+type term struct {
+	x string
+	k int64
+}
+
+// Symbolic returns an indexing.T whose values are linear expressions
+// over symbolic variables, such as 2*n+1.  Symbolic variables may be
+// named with Sym, and Var gives a fresh unnamed one.  Non-linear
+// operations give fresh variables bounded using the Intervals domain.
+//
+// The fresh variables of the result are not scoped, see SymbolicIn.
+func Symbolic() T {
+	return SymbolicIn("")
+}
+
+// SymbolicIn is like Symbolic, but the fresh variables are scoped
+// by scope, such as a package path.  Fresh variables are numbered in
+// the order they are created by the result, so values of different
+// indexing.Ts with the same scope must not be mixed.
+func SymbolicIn(scope string) T {
+	return symbolic{scope: scope, n: new(uint64)}
+}
+
+// Sym returns the symbolic variable named name, whose value is in
+// [lo, hi].  Names starting with '$' are reserved for fresh variables.
+func Sym(name string, lo, hi int64) I {
+	return &Expr{terms: []term{{x: name, k: 1}}, rng: Interval{lo, hi}}
+}
+
+// fresh returns a fresh variable whose value is in rng.  Fresh
+// variables are named by a '$', the scope of d followed by a '.' if
+// it is not empty, and a number, with a '$' suffix for those
+// introduced by widening.
+func (d symbolic) fresh(rng *Interval, widen bool) *Expr {
+	n := atomic.AddUint64(d.n, 1)
+	name := fmt.Sprintf("$%d", n)
+	if d.scope != "" {
+		name = fmt.Sprintf("$%s.%d", d.scope, n)
+	}
+	if widen {
+		name += "$"
+	}
+	return mkExpr(0, []term{{x: name, k: 1}}, rng)
+}
+
+func mkExpr(c int64, terms []term, rng *Interval) *Expr {
+	if rng.lo == rng.hi {
+		return &Expr{c: rng.lo, rng: *rng}
+	}
+	if len(terms) == 0 {
+		return &Expr{c: c, rng: Interval{c, c}}
+	}
+	return &Expr{c: c, terms: terms, rng: *rng}
+}
+
+func (e *Expr) isConst() bool {
+	return len(e.terms) == 0
+}
+
+func (e *Expr) Gen() I {
+	return &Expr{}
+}
+
+func (e *Expr) String() string {
+	var sb strings.Builder
+	for i, t := range e.terms {
+		if i > 0 {
+			sb.WriteString("+")
+		}
+		if t.k != 1 {
+			fmt.Fprintf(&sb, "%d*", t.k)
+		}
+		sb.WriteString(t.x)
+	}
+	if e.c != 0 || len(e.terms) == 0 {
+		if len(e.terms) > 0 {
+			sb.WriteString("+")
+		}
+		fmt.Fprintf(&sb, "%d", e.c)
+	}
+	return sb.String()
+}
+
+func (e *Expr) PlainEncode(w io.Writer) error {
+	err := plain.Put(w, "s")
+	if err != nil {
+		return err
+	}
+	for _, v := range [...]int64{e.c, e.rng.lo, e.rng.hi} {
+		if err = plain.EncodeInt64(w, v); err != nil {
+			return err
+		}
+	}
+	if err = plain.EncodeUint64(w, uint64(len(e.terms))); err != nil {
+		return err
+	}
+	for _, t := range e.terms {
+		if err = plain.EncodeInt64(w, t.k); err != nil {
+			return err
+		}
+		if err = plain.EncodeUint64(w, uint64(len(t.x))); err != nil {
+			return err
+		}
+		if err = plain.Put(w, t.x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Expr) PlainDecode(r io.Reader) error {
+	err := plain.Expect(r, "s")
+	if err != nil {
+		return err
+	}
+	var vs [3]int64
+	for i := range vs {
+		if vs[i], err = plain.DecodeInt64From(r); err != nil {
+			return err
+		}
+	}
+	n, err := plain.DecodeUint64From(r)
+	if err != nil {
+		return err
+	}
+	var terms []term
+	for i := uint64(0); i < n; i++ {
+		k, err := plain.DecodeInt64From(r)
+		if err != nil {
+			return err
+		}
+		sz, err := plain.DecodeUint64From(r)
+		if err != nil {
+			return err
+		}
+		buf := make([]byte, sz)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return err
+		}
+		terms = append(terms, term{x: string(buf), k: k})
+	}
+	e.c, e.rng, e.terms = vs[0], Interval{vs[1], vs[2]}, terms
+	return nil
+}
+
+var ivs = intervals{}
+
+func (d symbolic) Zero() I {
+	return d.FromInt64(0)
+}
+
+func (d symbolic) One() I {
+	return d.FromInt64(1)
+}
+
+func (d symbolic) IsVar(v I) bool {
+	return !v.(*Expr).isConst()
+}
+
+func (d symbolic) Var() I {
+	return d.fresh(ivs.Var().(*Interval), false)
+}
+
+func (d symbolic) FromInt64(v int64) I {
+	return &Expr{c: v, rng: Interval{v, v}}
+}
+
+func (d symbolic) ToInt64(v I) (int64, bool) {
+	e := v.(*Expr)
+	if !e.isConst() {
+		return 0, false
+	}
+	return e.c, true
+}
+
+// lin gives a + k*b, with wrapping arithmetic.
+func lin(a *Expr, k int64, b *Expr) (int64, []term) {
+	terms := make([]term, 0, len(a.terms)+len(b.terms))
+	i, j := 0, 0
+	for i < len(a.terms) || j < len(b.terms) {
+		switch {
+		case j == len(b.terms) || (i < len(a.terms) && a.terms[i].x < b.terms[j].x):
+			terms = append(terms, a.terms[i])
+			i++
+		case i == len(a.terms) || b.terms[j].x < a.terms[i].x:
+			terms = append(terms, term{x: b.terms[j].x, k: k * b.terms[j].k})
+			j++
+		default:
+			terms = append(terms, term{x: a.terms[i].x, k: a.terms[i].k + k*b.terms[j].k})
+			i++
+			j++
+		}
+		if n := len(terms); terms[n-1].k == 0 {
+			terms = terms[:n-1]
+		}
+	}
+	return a.c + k*b.c, terms
+}
+
+func (d symbolic) Plus(a, b I) I {
+	x, y := a.(*Expr), b.(*Expr)
+	c, terms := lin(x, 1, y)
+	return mkExpr(c, terms, ivs.Plus(&x.rng, &y.rng).(*Interval))
+}
+
+func (d symbolic) Times(a, b I) I {
+	x, y := a.(*Expr), b.(*Expr)
+	rng := ivs.Times(&x.rng, &y.rng).(*Interval)
+	if x.isConst() {
+		x, y = y, x
+	}
+	if !y.isConst() {
+		return d.fresh(rng, false)
+	}
+	c, terms := lin(&Expr{}, y.c, x)
+	return mkExpr(c, terms, rng)
+}
+
+// nonLinear gives the result of a non-linear operation, whose
+// bounds are rng.
+func (d symbolic) nonLinear(rng I, t xtruth.T) (I, xtruth.T) {
+	return d.fresh(rng.(*Interval), false), t
+}
+
+func (d symbolic) Div(a, b I) (I, xtruth.T) {
+	x, y := a.(*Expr), b.(*Expr)
+	return d.nonLinear(ivs.Div(&x.rng, &y.rng))
+}
+
+func (d symbolic) Rem(a, b I) (I, xtruth.T) {
+	x, y := a.(*Expr), b.(*Expr)
+	return d.nonLinear(ivs.Rem(&x.rng, &y.rng))
+}
+
+func (d symbolic) Band(a, b I) I {
+	x, y := a.(*Expr), b.(*Expr)
+	res, _ := d.nonLinear(ivs.Band(&x.rng, &y.rng), xtruth.True)
+	return res
+}
+
+func (d symbolic) Bnot(a I) I {
+	// ^x == -x - 1
+	x := a.(*Expr)
+	c, terms := lin(&Expr{c: -1}, -1, x)
+	return mkExpr(c, terms, ivs.Bnot(&x.rng).(*Interval))
+}
+
+func (d symbolic) Lshift(a, s I) (I, xtruth.T) {
+	x, y := a.(*Expr), s.(*Expr)
+	rng, t := ivs.Lshift(&x.rng, &y.rng)
+	if y.isConst() && y.c >= 0 && y.c < 64 {
+		c, terms := lin(&Expr{}, int64(1)<<uint(y.c), x)
+		return mkExpr(c, terms, rng.(*Interval)), t
+	}
+	return d.nonLinear(rng, t)
+}
+
+func (d symbolic) Rshift(a, s I) (I, xtruth.T) {
+	x, y := a.(*Expr), s.(*Expr)
+	return d.nonLinear(ivs.Rshift(&x.rng, &y.rng))
+}
+
+// diff gives a - b if it is a constant.
+func diff(a, b *Expr) (int64, bool) {
+	c, terms := lin(a, -1, b)
+	return c, len(terms) == 0
+}
+
+func (d symbolic) Less(a, b I) xtruth.T {
+	x, y := a.(*Expr), b.(*Expr)
+	if c, ok := diff(x, y); ok {
+		// x == y + c, which does not wrap if y's bounds
+		// plus c do not.
+		_, lok := addOK(y.rng.lo, c)
+		_, hok := addOK(y.rng.hi, c)
+		if lok && hok {
+			if c < 0 {
+				return xtruth.True
+			}
+			return xtruth.False
+		}
+	}
+	return ivs.Less(&x.rng, &y.rng)
+}
+
+func (d symbolic) Equal(a, b I) xtruth.T {
+	x, y := a.(*Expr), b.(*Expr)
+	if c, ok := diff(x, y); ok {
+		if c == 0 {
+			return xtruth.True
+		}
+		return xtruth.False
+	}
+	return ivs.Equal(&x.rng, &y.rng)
+}
+
+func (d symbolic) Join(a, b I) I {
+	if d.Equal(a, b) == xtruth.True {
+		return a
+	}
+	x, y := a.(*Expr), b.(*Expr)
+	return d.fresh(ivs.Join(&x.rng, &y.rng).(*Interval), false)
+}
+
+// Widen gives a if a and b are equal, or if a is a variable introduced
+// by widening whose bounds contain those of b.  Otherwise, it gives
+// a variable introduced by widening, whose bounds are the widening of
+// those of a and b.
+func (d symbolic) Widen(a, b I) I {
+	if d.Equal(a, b) == xtruth.True {
+		return a
+	}
+	x, y := a.(*Expr), b.(*Expr)
+	if len(x.terms) == 1 && x.c == 0 && x.terms[0].k == 1 && isWidened(x.terms[0].x) &&
+		x.rng.lo <= y.rng.lo && y.rng.hi <= x.rng.hi {
+		return a
+	}
+	return d.fresh(ivs.Widen(&x.rng, &y.rng).(*Interval), true)
+}
+
+// Bound bounds the interval of a by those of lo and hi.  The result
+// has the expression of a, unless the bounds make it a constant.
+func (d symbolic) Bound(a, lo, hi I) I {
+	x := a.(*Expr)
+	var l, h I
+	if lo != nil {
+		l = &lo.(*Expr).rng
+	}
+	if hi != nil {
+		h = &hi.(*Expr).rng
+	}
+	rng := ivs.Bound(&x.rng, l, h).(*Interval)
+	if *rng == x.rng {
+		return a
+	}
+	return mkExpr(x.c, x.terms, rng)
+}
+
+func isWidened(name string) bool {
+	return len(name) > 1 && name[0] == '$' && name[len(name)-1] == '$'
+}
+
+func (d symbolic) PlainEncode(w io.Writer) error {
+	return nil
+}
+
+func (d symbolic) PlainDecode(r io.Reader) error {
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-air/pal/internal/plain"
+	"github.com/go-air/pal/xtruth"
+)
+
+func TestSymbolicCoding(t *testing.T) {
+	idx := Symbolic()
+	n := Sym("n", 0, math.MaxInt64)
+	m := Sym("a.m", -4, 4)
+	e := idx.Plus(idx.Times(idx.FromInt64(2), n), idx.Plus(m, idx.One()))
+	for _, i := range [...]I{idx.Zero(), idx.FromInt64(-32), idx.Var(), n, e} {
+		err := plain.TestRoundTripClobber(i, func(c plain.Coder) {
+			*c.(*Expr) = Expr{}
+		}, false)
+		if err != nil {
+			t.Error(err)
+		}
+		if idx.Equal(i, i) != xtruth.True {
+			t.Errorf("%s != %s", i, i)
+		}
+	}
+}
+
+func TestSymbolicLinear(t *testing.T) {
+	idx := Symbolic()
+	n := Sym("n", 0, math.MaxInt64)
+	two := idx.FromInt64(2)
+	a := idx.Plus(idx.Times(two, n), idx.One())
+	b := idx.Plus(idx.Plus(n, n), idx.One())
+	if idx.Equal(a, b) != xtruth.True {
+		t.Errorf("%s != %s", a, b)
+	}
+	if idx.Equal(a, n) != xtruth.X {
+		t.Errorf("%s == %s not unknown", a, n)
+	}
+	if idx.Equal(b, idx.Times(two, n)) != xtruth.False {
+		t.Errorf("%s == 2*n", b)
+	}
+	if c, ok := idx.ToInt64(idx.Plus(a, idx.Times(idx.FromInt64(-2), n))); !ok || c != 1 {
+		t.Errorf("2*n+1-2*n != 1")
+	}
+	if sh, _ := idx.Lshift(n, idx.One()); idx.Equal(sh, idx.Times(two, n)) != xtruth.True {
+		t.Errorf("n<<1 != 2*n")
+	}
+	if idx.Equal(idx.Bnot(idx.Bnot(a)), a) != xtruth.True {
+		t.Errorf("^^a != a")
+	}
+}
+
+func TestSymbolicLess(t *testing.T) {
+	idx := Symbolic()
+	n := Sym("len(s)", 0, math.MaxInt64)
+	i := idx.Plus(n, idx.FromInt64(-1))
+	if idx.Less(i, n) != xtruth.True {
+		t.Errorf("%s < %s not true", i, n)
+	}
+	if idx.Less(n, i) != xtruth.False {
+		t.Errorf("%s < %s not false", n, i)
+	}
+	// i+1 may wrap
+	v := idx.Var()
+	if idx.Less(v, idx.Plus(v, idx.One())) != xtruth.X {
+		t.Errorf("v < v+1 not unknown")
+	}
+	if idx.Less(idx.Band(v, idx.FromInt64(3)), idx.FromInt64(4)) != xtruth.True {
+		t.Errorf("v&3 < 4 not true")
+	}
+	if idx.Less(idx.Band(v, idx.FromInt64(3)), n) != xtruth.X {
+		t.Errorf("v&3 < n not unknown")
+	}
+}
+
+func TestSymbolicWiden(t *testing.T) {
+	idx := Symbolic()
+	v := idx.Zero()
+	for i := 0; i < 8; i++ {
+		w := idx.Widen(v, idx.Join(v, idx.Plus(v, idx.One())))
+		if w == v {
+			return
+		}
+		v = w
+	}
+	t.Errorf("widen: no fixed point, %s", v)
+}
+
+func TestSymbolicBound(t *testing.T) {
+	idx := Symbolic()
+	v := idx.Var()
+	n := Sym("n", 2, 8)
+	b := idx.Bound(v, idx.Zero(), idx.Plus(n, idx.FromInt64(-1)))
+	if idx.Equal(b, v) != xtruth.True {
+		t.Errorf("%s != %s", b, v)
+	}
+	if idx.Less(b, idx.Zero()) != xtruth.False || idx.Less(b, idx.FromInt64(8)) != xtruth.True {
+		t.Errorf("%s not in [0, 7]", b)
+	}
+	if c, ok := idx.ToInt64(idx.Bound(v, idx.One(), idx.One())); !ok || c != 1 {
+		t.Errorf("%s in [1, 1] not 1", v)
+	}
+}
@@ -51,5 +51,15 @@ type T interface {
 	Equal(a, b I) xtruth.T
 	Less(a, b I) xtruth.T
 
+	// Join returns a value containing both a and b.
+	Join(a, b I) I
+	// Widen returns a value containing both a and b, such
+	// that any sequence v[i+1] = Widen(v[i], x[i]) stabilizes.
+	Widen(a, b I) I
+	// Bound returns a value containing the values of a which are
+	// at least lo and at most hi, where a nil lo or hi is no bound.
+	// It may return a if the domain cannot represent them.
+	Bound(a, lo, hi I) I
+
 	plain.Coder
 }
@@ -26,6 +26,9 @@ const (
 	IsFunc
 	IsParam
 	IsReturn
+	// IsUnsafe marks locations whose points-to sets may be unsound
+	// because they are derived from unsafe.Pointer or uintptr values.
+	IsUnsafe
 )
 
 const NoAttrs Attrs = 0
@@ -46,26 +49,32 @@ func (a Attrs) IsReturn() bool {
 	return a&IsReturn != 0
 }
 
+func (a Attrs) IsUnsafe() bool {
+	return a&IsUnsafe != 0
+}
+
+// PlainEncode encodes a as o±f±p±r±u±, with a '+' for each of
+// IsOpaque, IsFunc, IsParam, IsReturn and IsUnsafe which a has, and
+// a '-' otherwise.
 func (a Attrs) PlainEncode(w io.Writer) error {
 	_, e := w.Write([]byte(a.String()))
 	return e
 }
 
 var (
-	_Attrs [4]Attrs = [4]Attrs{
-		IsOpaque, IsFunc, IsParam, IsReturn}
+	_Attrs [5]Attrs = [5]Attrs{
+		IsOpaque, IsFunc, IsParam, IsReturn, IsUnsafe}
 )
 
+const attrsFlags = "ofpru"
+
 func (a *Attrs) decode(buf []byte) error {
-	if buf[0] != byte('o') ||
-		buf[2] != byte('f') ||
-		buf[4] != byte('p') ||
-		buf[6] != byte('r') {
-		return fmt.Errorf("expected: %s", string(buf))
-	}
 	*a = Attrs(0)
-	for i := 1; i < 8; i += 2 {
-		switch buf[i] {
+	for i := 0; i < len(buf); i += 2 {
+		if buf[i] != attrsFlags[i/2] {
+			return fmt.Errorf("expected: %s", string(buf))
+		}
+		switch buf[i+1] {
 		case '-':
 		case '+':
 			*a |= _Attrs[i/2]
@@ -76,13 +85,37 @@ func (a *Attrs) decode(buf []byte) error {
 	return nil
 }
 
+// PlainDecode decodes a as encoded by PlainEncode.  If r is an
+// io.ByteScanner, such as a bufio.Reader, PlainDecode also accepts
+// the encoding o±f±p±r± of results which predate IsUnsafe.
 func (a *Attrs) PlainDecode(r io.Reader) error {
-
-	buf := make([]byte, 8)
-	_, e := io.ReadFull(r, buf)
+	buf := make([]byte, 10)
+	_, e := io.ReadFull(r, buf[:8])
 	if e != nil {
 		return e
 	}
+	if bs, ok := r.(io.ByteScanner); ok {
+		c, e := bs.ReadByte()
+		switch {
+		case e == io.EOF:
+			return a.decode(buf[:8])
+		case e != nil:
+			return e
+		case c != 'u':
+			if e = bs.UnreadByte(); e != nil {
+				return e
+			}
+			return a.decode(buf[:8])
+		}
+		buf[8] = c
+		if buf[9], e = bs.ReadByte(); e != nil {
+			return e
+		}
+		return a.decode(buf)
+	}
+	if _, e = io.ReadFull(r, buf[8:]); e != nil {
+		return e
+	}
 	return a.decode(buf)
 }
 
@@ -101,5 +134,7 @@ func (a Attrs) String() string {
 		byte('p'),
 		boolByte(a.IsParam()),
 		byte('r'),
-		boolByte(a.IsReturn())})
+		boolByte(a.IsReturn()),
+		byte('u'),
+		boolByte(a.IsUnsafe())})
 }
@@ -15,13 +15,14 @@
 package memory
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/go-air/pal/internal/plain"
 )
 
 func TestAttrs(t *testing.T) {
-	org := IsOpaque | IsReturn
+	org := IsOpaque | IsReturn | IsUnsafe
 	attrs := org
 	p := &attrs
 	if err := plain.TestRoundTrip(p, false); err != nil {
@@ -31,3 +32,20 @@ func TestAttrs(t *testing.T) {
 		t.Fatalf("%s != %s\n", plain.String(p), plain.String(org))
 	}
 }
+
+func TestAttrsDecodeOld(t *testing.T) {
+	r := strings.NewReader("o+f-p-r+ x")
+	var a Attrs
+	if err := a.PlainDecode(r); err != nil {
+		t.Fatal(err)
+	}
+	if a != IsOpaque|IsReturn {
+		t.Errorf("%s != %s", a, IsOpaque|IsReturn)
+	}
+	if err := plain.Expect(r, " x"); err != nil {
+		t.Error(err)
+	}
+	if err := a.PlainDecode(strings.NewReader("o+f-p-r+")); err != nil || a != IsOpaque|IsReturn {
+		t.Errorf("at eof: %s %v", a, err)
+	}
+}
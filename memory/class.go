@@ -31,14 +31,15 @@ const (
 	Local
 	// Heap is the location associated with a heap allocation
 	Heap
-	// Rel is a relative location.  Rel has parent=root and
-	// non constant vsz, indicating the offset w.r.t. the root.
+	// Rel is a relative location.  A Rel location generated by
+	// Model.GenRel has parent=root and an offset w.r.t. the root
+	// which is an indexing.I, rather than its position in the
+	// region of the root.  Locations in structured data under a
+	// Rel location are also Rel locations.
 	//
-	// wsc: this will be necessary for unsafe.Pointer conversion
-	// and pointer arithmetic.
-	//
-	// Also, we can use it to model slice index addresses &slice[x].
-	//Rel
+	// Rel is used for pointer arithmetic (unsafe.Pointer conversion)
+	// and for slice index addresses &slice[x].
+	Rel
 )
 
 func (c Class) String() string {
@@ -51,6 +52,8 @@ func (c Class) String() string {
 		return "g"
 	case Heap:
 		return "h"
+	case Rel:
+		return "r"
 	default:
 		panic("bad MemClass")
 	}
@@ -76,6 +79,8 @@ func (c *Class) PlainDecode(r io.Reader) error {
 		*c = Global
 	case byte('h'):
 		*c = Heap
+	case byte('r'):
+		*c = Rel
 	default:
 		return fmt.Errorf("pal/memory.Class: unexpected class code: %s", string(buf))
 	}
@@ -95,17 +95,23 @@ func (c *Constraint) PlainEncode(w io.Writer) error {
 	}
 }
 
+// PlainDecode decodes c.  If the decoded constraint is a transfer, then
+// c.Index must be an indexing.I into which the index is decoded.
 func (c *Constraint) PlainDecode(r io.Reader) error {
 	err := plain.DecodeJoin(r, " ", &c.Kind, &c.Dest, &c.Src)
 	if err != nil {
 		return err
 	}
-	if c.Kind == KTransfer {
-		err = plain.Expect(r, " ")
-		if err != nil {
-			return err
-		}
-		err = c.Index.PlainDecode(r)
+	if c.Kind != KTransfer {
+		c.Index = nil
+		return nil
 	}
-	return err
+	if c.Index == nil {
+		return fmt.Errorf("transfer: no indexing")
+	}
+	err = plain.Expect(r, " ")
+	if err != nil {
+		return err
+	}
+	return c.Index.PlainDecode(r)
 }
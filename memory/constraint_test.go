@@ -36,7 +36,9 @@ func TestConstraint(t *testing.T) {
 		AddressOf(11, 32),
 		Load(12, 33),
 		Store(13, 34),
-		TransferIndex(14, 34, indexing.ConstVals().FromInt64(11))}
+		TransferIndex(14, 34, indexing.ConstVals().FromInt64(11)),
+		TransferIndex(15, 35, indexing.Intervals().Var()),
+		TransferIndex(16, 36, indexing.Symbolic().Var())}
 	for i := range d {
 		if err := plain.TestRoundTripClobber(&d[i], clob, true); err != nil {
 			t.Error(err)
@@ -49,7 +49,8 @@
 // The values of this internal structure are only accessible from the Model type.
 // Memory location classes indicate whether the memory is global, local (stack), or heap
 // allocated.  Memory location attributes indicate whether a location corresponds
-// to a parameter, a return, and whether it is opaque.
+// to a parameter, a return, and whether it is opaque or derived from unsafe
+// pointers.
 //
 // Structured Data
 //
@@ -66,6 +67,11 @@
 // for its region and a parent node, indicating to what structure it belongs.
 // The parent pointers are self-loops for roots.
 //
+// Locations of class Rel are relative to a root: their offset with respect
+// to the root is an index rather than a position in the region of the root.
+// They model memory addressed by indices which may not be constant, such as
+// the elements of slices.
+//
 // Constraints
 //
 // Each model has a set of associated constraints.  Constraints have a
@@ -89,4 +95,37 @@
 // src is a pointer to a struct.  i may be an int64 expression if src is a
 // pointer to an array or slice.
 //
+// When i is the constant 0, the transfer is a plain copy 'dst = src', and
+// structured data at src and dst is descended in tandem.  Otherwise, i is
+// an offset in units of logical size: for any v in the points to set of src,
+// v+i is in the points to set of dst if it is in the same region as v.  If i
+// is not a constant, v+i ranges over the siblings w of v (the locations
+// with the same parent as v) such that w-v may be equal to i.  For example,
+// the address of field f of a struct at v is v plus the logical offset of f,
+// and the address of an element of an array whose first element is at v is
+// v plus the index times the logical size of the elements.  Rel locations
+// with the same parent as v whose offset may be equal to that of v plus i
+// are also in the points to set of dst.
+//
+// Solving
+//
+// Model.Solve computes the least points to relation satisfying the
+// constraints.  Structured data is descended in tandem only to the extent of
+// the smaller of the two regions.  The nil location points to itself, and
+// loading or storing through it has no effect.
+//
+// Cycles of locations which must have the same points-to set are detected
+// during solving and collapsed.  Collapsing only identifies the points-to
+// sets of the locations involved, so the regions, parents, and roots of
+// locations are unaffected and the solution remains field sensitive.
+// Likewise, if enabled with SetSolveOpts, an offline pre-pass merges
+// locations whose points-to sets are provably equal, such as temporaries
+// copied from one another, before solving.  The representation of
+// points-to sets used while solving may also be chosen with SetSolveOpts,
+// see PtsRep, and solving may be done in parallel.  The solution is the
+// same whatever the options.
+//
+// Constraints may be added and locations generated after solving, in which
+// case Model.SolveIncremental only propagates the resulting new facts.
+//
 package memory
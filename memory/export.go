@@ -0,0 +1,220 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+// Export exports the model 'mod', removing unnecessary local mem.Locs and
+// compacting the result by permuting the remaining locations.  Export returns
+// the permutation if 'perm' is non-nil.
+//
+// Generally, after Export is called, 'mod' contains no local variables.  One
+// can retrieve points-to information for local variables using PointsToFor,
+// before calling Export.
+//
+// Export solves mod if it is not solved.  The locations which are kept are
+// those of class Global or Heap, those which are opaque, parameters or
+// returns, and those which are needed to express the constraints which may
+// still produce new points-to facts once opaque locations are bound by an
+// importer.  Any location pointed to by a kept location is also kept.
+// Locations are kept or removed together with their root.
+//
+// The constraints of the exported model consist of the solved relation
+// projected onto the kept locations, coded as address-of constraints,
+// together with the constraints which depend on opaque locations.
+//
+// The permutation maps each loc m of the model before export to its new loc
+// perm[m], which is NoLoc if m was removed.  The relative order of kept locs
+// is preserved.
+func (mod *Model) Export(perm []Loc) []Loc {
+	if !mod.solved() {
+		mod.SolveIncremental()
+	}
+	N := len(mod.locs)
+	dep := mod.opaqueDeps()
+
+	keep := make([]bool, N)
+	work := make([]Loc, 0, 128)
+	keepRoot := func(m Loc) {
+		r := mod.locs[m].root
+		if !keep[r] {
+			keep[r] = true
+			work = append(work, r)
+		}
+	}
+	keep[NoLoc] = true
+	keepRoot(mod.Zero())
+	for i := 2; i < N; i++ {
+		m := &mod.locs[i]
+		if m.root != Loc(i) {
+			continue
+		}
+		switch m.class {
+		case Global, Heap:
+			keepRoot(Loc(i))
+			continue
+		}
+		if m.attrs&(IsOpaque|IsParam|IsReturn) != 0 {
+			keepRoot(Loc(i))
+		}
+	}
+	cs := make([]Constraint, 0, len(mod.constraints))
+	for i := range mod.constraints {
+		c := &mod.constraints[i]
+		if c.Kind == KAddressOf || c.Dest == NoLoc || c.Src == NoLoc {
+			continue
+		}
+		if !dep[mod.locs[c.Dest].root] && !dep[mod.locs[c.Src].root] {
+			continue
+		}
+		cs = append(cs, *c)
+		keepRoot(c.Dest)
+		keepRoot(c.Src)
+	}
+	// close under points-to
+	for len(work) > 0 {
+		r := work[len(work)-1]
+		work = work[:len(work)-1]
+		mod.keepPointees(r, keepRoot)
+		for _, rel := range mod.relsOf(r) {
+			mod.keepPointees(rel, keepRoot)
+		}
+	}
+	// permute
+	retPerm := perm != nil
+	if cap(perm) < N {
+		perm = make([]Loc, N)
+	}
+	perm = perm[:N]
+	n := Loc(0)
+	for i := range mod.locs {
+		if keep[mod.locs[i].root] {
+			perm[i] = n
+			n++
+		} else {
+			perm[i] = NoLoc
+		}
+	}
+	locs := make([]loc, n)
+	pts := make([]locSet, n)
+	for i := range mod.locs {
+		pi := perm[i]
+		if i > 0 && pi == NoLoc {
+			continue
+		}
+		m := mod.locs[i]
+		m.root = perm[m.root]
+		m.parent = perm[m.parent]
+		m.obj = perm[m.obj]
+		locs[pi] = m
+		var ps locSet
+		for _, o := range mod.pts[i] {
+			ps = append(ps, perm[o])
+		}
+		pts[pi] = ps
+	}
+	for i := range cs {
+		c := &cs[i]
+		c.Dest = perm[c.Dest]
+		c.Src = perm[c.Src]
+	}
+	for i := range pts {
+		for _, o := range pts[i] {
+			cs = append(cs, AddressOf(Loc(i), o))
+		}
+	}
+	mod.locs = locs
+	mod.pts = pts
+	mod.constraints = cs
+	mod.work = mod.work[:0]
+	mod.solver = nil
+	mod.rels = nil
+	if !retPerm {
+		return nil
+	}
+	return perm
+}
+
+// keepPointees calls keep for every location pointed to by a location in
+// the region of m.
+func (mod *Model) keepPointees(m Loc, keep func(Loc)) {
+	end := m + Loc(mod.locs[m].lsz)
+	if end == m {
+		end++
+	}
+	for n := m; n < end; n++ {
+		for _, o := range mod.pts[n] {
+			keep(o)
+		}
+	}
+}
+
+// opaqueDeps computes, for each root, whether or not the points-to sets of
+// the locations in its region may depend on an opaque location, which is
+// to say whether or not they may change when mod is imported.
+//
+// opaqueDeps assumes mod is solved.
+func (mod *Model) opaqueDeps() []bool {
+	N := len(mod.locs)
+	dep := make([]bool, N)
+	for i := range mod.locs {
+		m := &mod.locs[i]
+		if m.attrs.IsOpaque() {
+			dep[m.root] = true
+		}
+	}
+	rootOf := func(m Loc) Loc {
+		return mod.locs[m].root
+	}
+	set := func(m Loc) bool {
+		r := rootOf(m)
+		if dep[r] {
+			return false
+		}
+		dep[r] = true
+		return true
+	}
+	for changed := true; changed; {
+		changed = false
+		for i := range mod.constraints {
+			c := &mod.constraints[i]
+			if c.Dest == NoLoc || c.Src == NoLoc {
+				continue
+			}
+			switch c.Kind {
+			case KTransfer:
+				if dep[rootOf(c.Src)] && set(c.Dest) {
+					changed = true
+				}
+			case KLoad:
+				d := dep[rootOf(c.Src)]
+				for _, o := range mod.pts[c.Src] {
+					d = d || dep[rootOf(o)]
+				}
+				if d && set(c.Dest) {
+					changed = true
+				}
+			case KStore:
+				if !dep[rootOf(c.Src)] && !dep[rootOf(c.Dest)] {
+					continue
+				}
+				for _, o := range mod.pts[c.Dest] {
+					if set(o) {
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	return dep
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/typeset"
+)
+
+func TestExport(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New())
+	x := mdl.Gen(gp.Class(Local).GoType(types.Typ[types.Int]))
+	u := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddAddressOf(u, x)
+
+	h := mdl.Gen(gp.Class(Heap).GoType(types.Typ[types.Int]))
+	g := mdl.Gen(gp.Class(Global).GoType(intPtrTy))
+	tmp := mdl.Gen(gp.Class(Local).GoType(intPtrTy))
+	mdl.AddAddressOf(tmp, h)
+	mdl.AddTransfer(g, tmp)
+
+	// *(*param) = tmp, depends on the opaque param.
+	_, pp := mdl.WithPointer(gp.Attrs(IsParam | IsOpaque).GoType(intPtrTy))
+	l := mdl.Gen(gp.Attrs(NoAttrs).GoType(intPtrTy))
+	mdl.AddLoad(l, pp)
+	mdl.AddStore(l, tmp)
+
+	N := mdl.Len()
+	perm := mdl.Export([]Loc{})
+	if len(perm) != N {
+		t.Fatalf("perm len %d != %d", len(perm), N)
+	}
+	if perm[x] != NoLoc || perm[u] != NoLoc {
+		t.Errorf("locals not removed: %v", perm)
+	}
+	for _, m := range []Loc{h, g, tmp, pp, l} {
+		if perm[m] == NoLoc {
+			t.Errorf("%d removed", m)
+		}
+	}
+	if perm[mdl.Zero()] != mdl.Zero() {
+		t.Errorf("zero moved")
+	}
+	if mdl.Len() != N-2 {
+		t.Errorf("len %d != %d", mdl.Len(), N-2)
+	}
+	checkPts(t, mdl, perm[g], perm[h])
+	checkPts(t, mdl, perm[tmp], perm[h])
+	found := false
+	for _, c := range mdl.constraints {
+		if c.Kind == KStore && c.Dest == perm[l] && c.Src == perm[tmp] {
+			found = true
+		}
+		if c.Dest == NoLoc || c.Src == NoLoc {
+			t.Errorf("dangling constraint %v", c)
+		}
+	}
+	if !found {
+		t.Errorf("opaque dependent store removed")
+	}
+	if mdl.Export(nil) != nil {
+		t.Errorf("perm for nil")
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import "encoding/binary"
+
+// hvn is an offline pre-pass which merges locations with provably equal
+// points-to sets, using hash-based value numbering with union (in the style
+// of Hardekopf and Lin's HVN and HU).
+//
+// hvn is called after addConstraints, when the plain transfer edges are in the graph
+// and before any complex constraint has been applied.  The locations whose
+// points-to sets may grow other than by plain transfers, such as load
+// destinations and anything which may be stored to, are given a fresh label.
+// Every address taken gives a label, and other locations are labelled with
+// the union of the labels of their predecessors.  Locations with equal non
+// empty sets of labels have equal points-to sets and are merged.
+func (s *solver) hvn() {
+	mod := s.mod
+	N := len(mod.locs)
+
+	// collapse cycles of plain transfers.
+	for i := 2; i < N; i++ {
+		s.lcdCands = append(s.lcdCands, Loc(i))
+	}
+	s.collapseCycles()
+
+	indirect := make([]bool, N)
+	adrTaken := make([]bool, N) // by root
+	s.adrTaken = adrTaken
+	for i := range mod.constraints {
+		c := &mod.constraints[i]
+		if c.Dest == NoLoc || c.Src == NoLoc {
+			continue
+		}
+		switch c.Kind {
+		case KAddressOf:
+			adrTaken[mod.locs[c.Src].root] = true
+		case KLoad:
+			end := c.Dest + Loc(mod.locs[c.Dest].lsz)
+			for m := c.Dest; m < end || m == c.Dest; m++ {
+				indirect[m] = true
+			}
+		case KTransfer:
+			if !s.isZero(c.Index) {
+				indirect[c.Dest] = true
+			}
+		}
+	}
+	for i := 1; i < N; i++ {
+		if adrTaken[mod.locs[i].root] {
+			indirect[i] = true
+		}
+	}
+
+	// own labels: addresses are labelled by the loc, fresh labels follow.
+	labels := make([][]uint32, N)
+	fresh := uint32(N)
+	for i := 1; i < N; i++ {
+		if !indirect[i] {
+			continue
+		}
+		r := s.find(Loc(i))
+		if len(labels[r]) == 0 {
+			labels[r] = append(labels[r], fresh)
+			fresh++
+		}
+	}
+	for i := range mod.constraints {
+		c := &mod.constraints[i]
+		if c.Kind != KAddressOf || c.Dest == NoLoc || c.Src == NoLoc {
+			continue
+		}
+		r := s.find(c.Dest)
+		labels[r] = unionLabels(labels[r], []uint32{uint32(c.Src)})
+	}
+
+	// propagate in topological order.
+	indeg := make([]int, N)
+	for i := 1; i < N; i++ {
+		if s.rep[i] != Loc(i) {
+			continue
+		}
+		for _, m := range s.succs[i] {
+			indeg[s.find(m)]++
+		}
+	}
+	order := make([]Loc, 0, N)
+	for i := 1; i < N; i++ {
+		if s.rep[i] == Loc(i) && indeg[i] == 0 {
+			order = append(order, Loc(i))
+		}
+	}
+	for j := 0; j < len(order); j++ {
+		n := order[j]
+		for _, m := range s.succs[n] {
+			m = s.find(m)
+			labels[m] = unionLabels(labels[m], labels[n])
+			indeg[m]--
+			if indeg[m] == 0 {
+				order = append(order, m)
+			}
+		}
+	}
+
+	// number and merge.
+	s.hvnMerged = make([]bool, N)
+	vns := make(map[string]Loc)
+	var buf []byte
+	var tmp [binary.MaxVarintLen32]byte
+	for _, n := range order {
+		ls := labels[n]
+		if len(ls) == 0 {
+			continue
+		}
+		buf = buf[:0]
+		for _, l := range ls {
+			k := binary.PutUvarint(tmp[:], uint64(l))
+			buf = append(buf, tmp[:k]...)
+		}
+		k := string(buf)
+		if r, ok := vns[k]; ok {
+			r = s.unify(s.find(r), n)
+			s.hvnMerged[r] = true
+			vns[k] = r
+			continue
+		}
+		vns[k] = n
+	}
+	for i := range s.hvnMerged {
+		s.hvnMerged[i] = s.hvnMerged[s.find(Loc(i))]
+	}
+}
+
+// unionLabels returns the union of the sorted sets a and b, reusing a
+// if possible.
+func unionLabels(a, b []uint32) []uint32 {
+	if len(b) == 0 {
+		return a
+	}
+	res := make([]uint32, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			res = append(res, a[i])
+			i++
+		case b[j] < a[i]:
+			res = append(res, b[j])
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	res = append(res, a[i:]...)
+	res = append(res, b[j:]...)
+	if len(res) == len(a) {
+		return a
+	}
+	return res
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"go/types"
+	"math/rand"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/typeset"
+)
+
+// randModel generates a pseudo random model from seed.
+func randModel(seed int64, nLocs, nCons int) *Model {
+	rnd := rand.New(rand.NewSource(seed))
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New()).Class(Local)
+	tys := []types.Type{
+		types.Typ[types.Int],
+		intPtrTy,
+		types.NewPointer(intPtrTy),
+		pairTy,
+		arrTy}
+	for i := 0; i < nLocs; i++ {
+		mdl.Gen(gp.GoType(tys[rnd.Intn(len(tys))]))
+	}
+	N := mdl.Len()
+	loc := func() Loc {
+		return Loc(1 + rnd.Intn(N-1))
+	}
+	for i := 0; i < nCons; i++ {
+		a, b := loc(), loc()
+		switch rnd.Intn(6) {
+		case 0:
+			mdl.AddAddressOf(a, b)
+		case 1:
+			mdl.AddLoad(a, b)
+		case 2:
+			mdl.AddStore(a, b)
+		case 3:
+			mdl.AddTransferIndex(a, b, mdl.indexing.FromInt64(int64(rnd.Intn(3))))
+		default:
+			mdl.AddTransfer(a, b)
+		}
+	}
+	return mdl
+}
+
+func TestHVN(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New()).Class(Local)
+	x := mdl.Gen(gp.GoType(types.Typ[types.Int]))
+	p := mdl.Gen(gp.GoType(intPtrTy))
+	q := mdl.Gen(gp.GoType(intPtrTy))
+	r := mdl.Gen(gp.GoType(intPtrTy))
+	u := mdl.Gen(gp.GoType(intPtrTy))
+	v := mdl.Gen(gp.GoType(intPtrTy))
+	pp := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	d := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddAddressOf(p, x)
+	mdl.AddAddressOf(u, x)
+	mdl.AddAddressOf(v, x)
+	mdl.AddTransfer(q, p)
+	mdl.AddTransfer(r, q)
+	mdl.AddAddressOf(pp, u)
+	mdl.AddLoad(d, pp)
+
+	s := newSolver(mdl)
+	s.addConstraints()
+	s.hvn()
+	if s.find(p) != s.find(q) || s.find(q) != s.find(r) || s.find(r) != s.find(v) {
+		t.Errorf("p q r v not merged")
+	}
+	if s.find(u) == s.find(p) {
+		t.Errorf("address taken u merged")
+	}
+	if s.find(d) == s.find(p) {
+		t.Errorf("load destination merged")
+	}
+	s.solve()
+	mdl.pts = s.result()
+	for _, m := range []Loc{p, q, r, u, v, d} {
+		checkPts(t, mdl, m, x)
+	}
+}
+
+func TestHVNRandom(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		a := randModel(seed, 40, 80)
+		a.SetSolveOpts(SolveOpts{HVN: false})
+		a.Solve()
+		b := randModel(seed, 40, 80)
+		b.SetSolveOpts(SolveOpts{HVN: true})
+		b.Solve()
+		for i := 0; i < a.Len(); i++ {
+			m := Loc(i)
+			checkPts(t, b, m, a.PointsToFor(nil, m)...)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import "github.com/go-air/pal/typeset"
+
+// Import imports 'other', merging it with mod in place.  Import returns the
+// relocation of the locs of 'other' if 'perm' is non-nil.
+//
+// The locations of other are appended to those of mod, and the constraints
+// of other are appended to those of mod, with every location relocated.
+// The nil location of other is relocated to the nil location of mod.
+// 'other' is typically the result of Export, and is not modified.
+//
+// The types of the locations of other are relocated by 'tperm', which is
+// the relocation of the TypeSet of other into that of mod given by
+// typeset.TypeSet.Import.  If 'tperm' is nil, mod and other must share a
+// TypeSet.
+//
+// Once imported, the importer binds the locations it uses to represent
+// symbols of 'other', such as opaque exported globals or functions, to
+// their relocated counterparts with a transfer 'use = perm[def]'.  Since
+// functions are laid out with their parameters and results as children,
+// this single transfer also binds parameters and results.
+//
+// The points-to relation of mod is not updated by Import, mod must be
+// solved again.
+func (mod *Model) Import(other *Model, tperm []typeset.Type, perm []Loc) []Loc {
+	N := len(other.locs)
+	retPerm := perm != nil
+	if cap(perm) < N {
+		perm = make([]Loc, N)
+	}
+	perm = perm[:N]
+	base := Loc(uint32(len(mod.locs))) - 2
+	perm[NoLoc] = NoLoc
+	if N > 1 {
+		perm[other.Zero()] = mod.Zero()
+	}
+	for i := 2; i < N; i++ {
+		perm[i] = base + Loc(i)
+	}
+	for i := 2; i < N; i++ {
+		m := other.locs[i]
+		m.root = perm[m.root]
+		m.parent = perm[m.parent]
+		m.obj = perm[m.obj]
+		if tperm != nil {
+			m.typ = tperm[m.typ]
+		}
+		m.mark = 0
+		mod.locs = append(mod.locs, m)
+	}
+	mod.rels = nil
+	for _, c := range other.constraints {
+		c.Dest = perm[c.Dest]
+		c.Src = perm[c.Src]
+		if c.Kind == KAddressOf && c.Dest == mod.Zero() && c.Src == mod.Zero() {
+			// already in mod
+			continue
+		}
+		mod.constraints = append(mod.constraints, c)
+	}
+	if !retPerm {
+		return nil
+	}
+	return perm
+}
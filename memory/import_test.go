@@ -0,0 +1,78 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/typeset"
+)
+
+func TestImport(t *testing.T) {
+	// dependency: exported opaque global g, with 'h = *g'
+	// where h is an exported global.
+	dep := NewModel(indexing.ConstVals())
+	dts := typeset.New()
+	gp := NewGenParams(dts)
+	gp.Class(Global).Attrs(IsOpaque)
+	g, gptr := dep.WithPointer(gp.GoType(intPtrTy))
+	_, hptr := dep.WithPointer(gp.GoType(intPtrTy))
+	tmp := dep.Gen(gp.Class(Local).Attrs(NoAttrs).GoType(intPtrTy))
+	dep.AddLoad(tmp, gptr)
+	dep.AddStore(hptr, tmp)
+	dperm := dep.Export([]Loc{})
+	g, gptr, hptr = dperm[g], dperm[gptr], dperm[hptr]
+
+	mdl := NewModel(indexing.ConstVals())
+	ts := typeset.New()
+	gp = NewGenParams(ts)
+	// types of mdl not in dep, so that the types of dep move.
+	ts.FromGoType(types.NewSlice(types.Typ[types.Float64]))
+	x := mdl.Gen(gp.Class(Global).GoType(types.Typ[types.Int]))
+	perm := mdl.Import(dep, ts.Import(dts), []Loc{})
+	if perm[dep.Zero()] != mdl.Zero() {
+		t.Errorf("zero not relocated to zero")
+	}
+	for _, m := range [...]Loc{g, gptr, hptr} {
+		dty, ty := dep.Type(m), mdl.Type(perm[m])
+		if ts.Kind(ty) != dts.Kind(dty) || ts.Lsize(ty) != dts.Lsize(dty) {
+			t.Errorf("type of %d not relocated", m)
+		}
+	}
+	if ty := mdl.Type(perm[g]); ty != ts.FromGoType(intPtrTy) {
+		t.Errorf("type of %d not relocated to *int", perm[g])
+	}
+	// the importer's view of the dependency's globals
+	useG := mdl.Gen(gp.Class(Local).GoType(types.NewPointer(intPtrTy)))
+	useH := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	mdl.AddTransfer(useG, perm[gptr])
+	mdl.AddTransfer(useH, perm[hptr])
+	px := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddAddressOf(px, x)
+	mdl.AddStore(useG, px) // g = &x
+	d := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddLoad(d, useH) // d = h
+
+	mdl.Solve()
+	checkPts(t, mdl, useG, perm[g])
+	checkPts(t, mdl, perm[g], x)
+	checkPts(t, mdl, d, x)
+
+	if mdl.Import(dep, nil, nil) != nil {
+		t.Errorf("perm for nil")
+	}
+}
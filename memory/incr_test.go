@@ -0,0 +1,90 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/typeset"
+)
+
+func TestSolveIncremental(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	mdl.SetSolveOpts(SolveOpts{})
+	gp := NewGenParams(typeset.New()).Class(Local)
+	x := mdl.Gen(gp.GoType(types.Typ[types.Int]))
+	p := mdl.Gen(gp.GoType(intPtrTy))
+	q := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddAddressOf(p, x)
+	mdl.AddTransfer(q, p)
+	mdl.Solve()
+	s := mdl.solver
+	checkPts(t, mdl, q, x)
+
+	y := mdl.Gen(gp.GoType(types.Typ[types.Int]))
+	pp := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	d := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddAddressOf(pp, q)
+	mdl.AddLoad(d, pp)
+	mdl.AddAddressOf(p, y)
+	mdl.SolveIncremental()
+	if mdl.solver != s {
+		t.Errorf("solved from scratch")
+	}
+	checkPts(t, mdl, q, x, y)
+	checkPts(t, mdl, d, x, y)
+
+	// with hvn, p and r are merged, so adding to p alone
+	// requires solving from scratch.
+	r := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddTransfer(r, p)
+	mdl.SetSolveOpts(SolveOpts{HVN: true})
+	mdl.Solve()
+	s = mdl.solver
+	z := mdl.Gen(gp.GoType(types.Typ[types.Int]))
+	mdl.AddAddressOf(p, z)
+	mdl.SolveIncremental()
+	if mdl.solver == s {
+		t.Errorf("hvn merge not invalidated")
+	}
+	checkPts(t, mdl, p, x, y, z)
+	checkPts(t, mdl, r, x, y, z)
+}
+
+func TestSolveIncrementalRandom(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		a := randModel(seed, 40, 80)
+		a.Solve()
+		for _, hvn := range []bool{false, true} {
+			for _, par := range []int{0, 4} {
+				b := randModel(seed, 40, 80)
+				b.SetSolveOpts(SolveOpts{HVN: hvn, Parallel: par})
+				cs := b.constraints
+				for k := len(cs) / 4; k < len(cs); k += len(cs) / 4 {
+					b.constraints = cs[:k]
+					b.SolveIncremental()
+				}
+				b.constraints = cs
+				b.SolveIncremental()
+				for i := 0; i < a.Len(); i++ {
+					m := Loc(i)
+					checkPts(t, b, m, a.PointsToFor(nil, m)...)
+				}
+			}
+		}
+	}
+}
@@ -15,9 +15,11 @@
 package memory
 
 import (
+	"fmt"
 	"go/token"
 	"io"
 
+	"github.com/go-air/pal/indexing"
 	"github.com/go-air/pal/internal/plain"
 	"github.com/go-air/pal/typeset"
 )
@@ -58,6 +60,8 @@ type loc struct {
 
 	obj Loc // for locals and globals passed by addr...  NoLoc if unknown
 
+	rel indexing.I // offset w.r.t. root, for class Rel
+
 	mark int // scratch space for internal algos
 }
 
@@ -85,12 +89,33 @@ func (p *plainPos) PlainDecode(r io.Reader) error {
 }
 
 func (m *loc) PlainEncode(w io.Writer) error {
-	return plain.EncodeJoin(w, " ", m.class, m.attrs, plainPos(m.pos), m.parent, m.obj)
+	err := plain.EncodeJoin(w, " ", m.class, m.attrs, plainPos(m.pos), m.root, m.parent, plain.Uint(m.lsz), m.obj, m.typ)
+	if err != nil || m.class != Rel {
+		return err
+	}
+	if err = plain.Put(w, " "); err != nil {
+		return err
+	}
+	return m.rel.PlainEncode(w)
 }
 
+// PlainDecode decodes m.  If the decoded class is Rel, then m.rel must be
+// an indexing.I into which the offset is decoded.
 func (m *loc) PlainDecode(r io.Reader) error {
 	pp := plainPos(m.pos)
-	err := plain.DecodeJoin(r, " ", &m.class, &m.attrs, &pp, &m.parent, &m.obj)
+	lsz := plain.Uint(m.lsz)
+	err := plain.DecodeJoin(r, " ", &m.class, &m.attrs, &pp, &m.root, &m.parent, &lsz, &m.obj, &m.typ)
 	m.pos = token.Pos(pp)
-	return err
+	m.lsz = int(lsz)
+	if err != nil || m.class != Rel {
+		m.rel = nil
+		return err
+	}
+	if m.rel == nil {
+		return fmt.Errorf("rel loc: no indexing")
+	}
+	if err = plain.Expect(r, " "); err != nil {
+		return err
+	}
+	return m.rel.PlainDecode(r)
 }
@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/go-air/pal/internal/plain"
+	"github.com/go-air/pal/typeset"
 )
 
 func TestLoc(t *testing.T) {
@@ -33,13 +34,14 @@ func TestLoc(t *testing.T) {
 }
 
 func TestLittleLoc(t *testing.T) {
-	org := loc{parent: Loc(10011), class: Heap, attrs: IsOpaque}
+	org := loc{root: Loc(10010), parent: Loc(10011), lsz: 3, class: Heap, attrs: IsOpaque, typ: typeset.String}
 	m := org
 	p := &m
 	if err := plain.TestRoundTrip(p, false); err != nil {
 		t.Fatal(err)
 	}
-	if p.parent != org.parent || p.class != org.class || p.attrs != org.attrs {
+	if p.root != org.root || p.parent != org.parent || p.lsz != org.lsz ||
+		p.class != org.class || p.attrs != org.attrs || p.typ != org.typ {
 		t.Fatalf("%s != %s\n", plain.String(p), plain.String(&org))
 	}
 }
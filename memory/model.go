@@ -33,6 +33,10 @@ type Model struct {
 	constraints []Constraint
 	indexing    indexing.T
 	work        []Loc
+	pts         []locSet // solved points-to sets, indexed by Loc
+	solveOpts   SolveOpts
+	solver      *solver // state of the last solve, for SolveIncremental
+	rels        map[Loc][]Loc // parent -> Rel children, nil if not computed
 }
 
 // NewModel generates a new memory model for a package.
@@ -46,7 +50,8 @@ func NewModel(index indexing.T) *Model {
 		// 1 -> Zero / nil/null
 		locs:        make([]loc, 2, 1024),
 		constraints: make([]Constraint, 0, 1024),
-		indexing:    index}
+		indexing:    index,
+		solveOpts:   DefaultSolveOpts()}
 	zz := Loc(1)
 	z := &res.locs[1]
 	z.class = Zero
@@ -120,6 +125,12 @@ func (mod *Model) Lsize(m Loc) int {
 	return mod.locs[m].lsz
 }
 
+// Overlaps returns whether or not the memory of a and b may overlap.
+//
+// Locations in distinct regions do not overlap.  Within a region, the
+// memory of a location consists of the location and those in the structured
+// data under it, so a location overlaps with its ancestors and descendants,
+// and not with its siblings nor their descendants.
 func (mod *Model) Overlaps(a, b Loc) xtruth.T {
 	if mod.Root(a) != mod.Root(b) {
 		return xtruth.False
@@ -127,20 +138,105 @@ func (mod *Model) Overlaps(a, b Loc) xtruth.T {
 	if a == b {
 		return xtruth.True
 	}
-	return xtruth.X
+	idx := mod.indexing
+	la, ha := mod.span(a)
+	lb, hb := mod.span(b)
+	le := func(x, y indexing.I) xtruth.T {
+		return idx.Less(y, x).Not()
+	}
+	if le(lb, la).And(le(ha, hb)) == xtruth.True {
+		return xtruth.True
+	}
+	if le(la, lb).And(le(hb, ha)) == xtruth.True {
+		return xtruth.True
+	}
+	return idx.Less(la, hb).And(idx.Less(lb, ha))
 }
 
+// Equals returns whether or not a and b are the same memory.
 func (mod *Model) Equals(a, b Loc) xtruth.T {
-	if a != b {
+	if a == b {
+		return xtruth.True
+	}
+	if mod.Root(a) != mod.Root(b) {
 		return xtruth.False
 	}
-	return xtruth.X
+	idx := mod.indexing
+	la, ha := mod.span(a)
+	lb, hb := mod.span(b)
+	return idx.Equal(la, lb).And(idx.Equal(ha, hb))
+}
+
+// span gives the offsets, relative to the root of m, of m and of the
+// location just past the region of m.
+func (mod *Model) span(m Loc) (lo, hi indexing.I) {
+	idx := mod.indexing
+	sz := int64(mod.locs[m].lsz)
+	if sz == 0 {
+		sz = 1
+	}
+	lo = mod.offset(m)
+	return lo, idx.Plus(lo, idx.FromInt64(sz))
+}
+
+// offset gives the offset of m relative to its root.
+func (mod *Model) offset(m Loc) indexing.I {
+	mm := &mod.locs[m]
+	if mm.class == Rel {
+		return mm.rel
+	}
+	return mod.indexing.FromInt64(int64(m) - int64(mm.root))
+}
+
+// GenRel generates a Rel location, with the memory described by gp, at
+// offset 'off' from the root of 'base', and returns it.
+//
+// The result has the root of base as parent and root, and is not part of
+// the region of the root; it is rather found by adding to pointers into
+// the region indices which may equal its offset.  The structured data under
+// the result is generated as with Gen, except that it is of class Rel, with
+// offsets relative to off.
+func (mod *Model) GenRel(gp *GenParams, base Loc, off indexing.I) Loc {
+	idx := mod.indexing
+	r := mod.locs[base].root
+	class := gp.class
+	gp.class = Rel
+	var sum int
+	n := Loc(uint32(len(mod.locs)))
+	mod.add(gp, r, r, &sum)
+	gp.class = class
+	for m := n; m < Loc(uint32(len(mod.locs))); m++ {
+		mod.locs[m].rel = idx.Plus(off, idx.FromInt64(int64(m-n)))
+	}
+	if mod.rels != nil {
+		mod.rels[r] = append(mod.rels[r], n)
+	}
+	mod.genObjs(gp)
+	return n
+}
+
+// relsOf returns the Rel locations whose parent is p.
+func (mod *Model) relsOf(p Loc) []Loc {
+	if mod.rels == nil {
+		mod.rels = make(map[Loc][]Loc)
+		for i := range mod.locs {
+			m := &mod.locs[i]
+			if m.class == Rel && mod.locs[m.parent].class != Rel {
+				mod.rels[m.parent] = append(mod.rels[m.parent], Loc(i))
+			}
+		}
+	}
+	return mod.rels[p]
 }
 
 func (mod *Model) Zero() Loc {
 	return Loc(1)
 }
 
+func (mod *Model) Class(m Loc) Class {
+	return mod.locs[m].class
+}
+
 func (mod *Model) Type(m Loc) typeset.Type {
 	return mod.locs[m].typ
 }
@@ -149,16 +245,22 @@ func (mod *Model) Gen(gp *GenParams) Loc {
 	var sum int
 	p := Loc(uint32(len(mod.locs)))
 	result := mod.add(gp, p, p, &sum)
+	mod.genObjs(gp)
+	return result
+}
+
+// genObjs generates the objects pointed to by the pointers in mod.work,
+// which are function parameter and result slots.
+func (mod *Model) genObjs(gp *GenParams) {
 	for _, ptr := range mod.work {
 		gp.typ = gp.ts.Elem(mod.Type(ptr))
 		sum := 0
-		p = Loc(uint32(len(mod.locs)))
+		p := Loc(uint32(len(mod.locs)))
 		obj := mod.add(gp, p, p, &sum)
 		mod.locs[ptr].obj = obj
 		mod.AddAddressOf(ptr, obj)
 	}
 	mod.work = mod.work[:0]
-	return result
 }
 
 func (mod *Model) WithPointer(gp *GenParams) (obj, ptr Loc) {
@@ -171,6 +273,7 @@ func (mod *Model) WithPointer(gp *GenParams) (obj, ptr Loc) {
 		typ:    gp.typ,
 		parent: ptr,
 		root:   ptr,
+		lsz:    1,
 		obj:    obj})
 	mod.AddAddressOf(ptr, obj)
 	return
@@ -179,7 +282,24 @@ func (mod *Model) WithPointer(gp *GenParams) (obj, ptr Loc) {
 // PlainCoderAt returns a plain.Coder for the information
 // associated with memory at index i.
 func (mod *Model) PlainCoderAt(i int) plain.Coder {
-	return &mod.locs[i]
+	return &locCoder{mod: mod, m: &mod.locs[i]}
+}
+
+// locCoder codes a loc, decoding Rel offsets with the
+// indexing of mod.
+type locCoder struct {
+	mod *Model
+	m   *loc
+}
+
+func (c *locCoder) PlainEncode(w io.Writer) error {
+	return c.m.PlainEncode(w)
+}
+
+func (c *locCoder) PlainDecode(r io.Reader) error {
+	c.m.rel = c.mod.indexing.Zero().Gen()
+	c.mod.rels = nil
+	return c.m.PlainDecode(r)
 }
 
 // Cap destructively changes the total size of mod.
@@ -191,6 +311,7 @@ func (mod *Model) Cap(c int) {
 		mod.locs = tmp
 	}
 	mod.locs = mod.locs[:c]
+	mod.rels = nil
 }
 
 // add adds a root recursively according to ty.
@@ -208,7 +329,6 @@ func (mod *Model) add(gp *GenParams, p, r Loc, sum *int) Loc {
 		pos:    gp.pos,
 		typ:    gp.typ}
 	lastSum := *sum
-	added := true
 	switch gp.ts.Kind(gp.typ) {
 	// these are added as pointers here indirect associattions (params,
 	// returns, ...) are done in github.com/go-air/objects.Builder
@@ -250,8 +370,7 @@ func (mod *Model) add(gp *GenParams, p, r Loc, sum *int) Loc {
 		}
 	case typeset.Named:
 		gp.typ = gp.ts.Underlying(gp.typ)
-		mod.add(gp, p, r, sum)
-		added = false
+		return mod.add(gp, p, r, sum)
 	case typeset.Func:
 		mod.locs = append(mod.locs, l)
 		*sum++
@@ -261,7 +380,8 @@ func (mod *Model) add(gp *GenParams, p, r Loc, sum *int) Loc {
 			gp.typ = gp.ts.PointerTo(rcvty)
 			mod.work = append(mod.work, mod.add(gp, n, r, sum))
 		}
-		// TBD FreeVars
+		// free variables are not part of the type, see
+		// objects.Builder.AddFree
 
 		np := gp.ts.NumParams(fty)
 		for i := 0; i < np; i++ {
@@ -279,12 +399,9 @@ func (mod *Model) add(gp *GenParams, p, r Loc, sum *int) Loc {
 	default:
 		panic(fmt.Sprintf("%d: unexpected/unimplemented", gp.typ))
 	}
-	if added {
-		// we added a slot at dst[n] for ty,  set its size
-		mod.locs[n].lsz = *sum - lastSum
-		return n
-	}
-	return NoLoc
+	// we added a slot at dst[n] for ty,  set its size
+	mod.locs[n].lsz = *sum - lastSum
+	return n
 }
 
 func (mod *Model) Attrs(m Loc) Attrs {
@@ -326,31 +443,110 @@ func (mod *Model) AddTransferIndex(dst, src Loc, i indexing.I) {
 	mod.constraints = append(mod.constraints, TransferIndex(dst, src, i))
 }
 
+// Solve computes the points-to relation of mod by applying the constraints
+// of mod until a fixed point is reached.
+//
+// Solve may be called multiple times, each call computes the relation from
+// scratch.
 func (mod *Model) Solve() {
-	// apply constraints until fixed point.
+	s := newSolver(mod)
+	s.addConstraints()
+	if mod.solveOpts.HVN {
+		s.hvn()
+	}
+	mod.solver = s
+	mod.runSolver()
 }
 
-// PointsTo places the points-to set of
-// p in dst and returns it.
-func (mod *Model) PointsToFor(dst []Loc, p Loc) []Loc {
-	return dst
+// SolveIncremental is like Solve, but only propagates the facts resulting
+// from the constraints added and the locations generated since the last
+// call to Solve or SolveIncremental.
+//
+// SolveIncremental computes the relation from scratch if mod has not been
+// solved, if mod has been exported since it was solved, or if the
+// constraints added invalidate the equivalences found by the HVN pre-pass
+// of the last solve.  Locations and constraints imported with Import are
+// solved incrementally like any others.  In any event, the result is the
+// same as that of Solve.
+func (mod *Model) SolveIncremental() {
+	s := mod.solver
+	if s == nil || !s.grow() {
+		mod.Solve()
+		return
+	}
+	s.addConstraints()
+	mod.runSolver()
+}
+
+func (mod *Model) runSolver() {
+	s := mod.solver
+	// computed here, as offsets may be called concurrently and the
+	// cache may have been reset since the solver was created.
+	mod.relsOf(NoLoc)
+	if mod.solveOpts.Parallel > 1 {
+		s.solveParallel(mod.solveOpts.Parallel)
+	} else {
+		s.solve()
+	}
+	mod.pts = s.result()
 }
 
-// Export exports the model 'mod', removing unnecessary local mem.Locs and
-// compacting the result by permuting the remaining locations.  Export returns
-// the permutation if 'perm' is non-nil.
-//
-// Generally, after Export is called, 'mod' contains no local variables.  One
-// can retrieve points-to information for local variables using PoinstToFor,
-// before calling Export.
-func (mod *Model) Export(perm []Loc) []Loc {
-	return perm
+// solved returns whether the points-to relation of mod reflects all
+// locations and constraints.
+func (mod *Model) solved() bool {
+	if len(mod.pts) != len(mod.locs) {
+		return false
+	}
+	return mod.solver == nil || mod.solver.nCons == len(mod.constraints)
 }
 
-// Import imports 'other', merging it with
-// mod in place.
-func (mod *Model) Import(other *Model) {
+// SolveOpts gives options for Model.Solve.
+type SolveOpts struct {
+	// HVN enables an offline pre-pass which finds locations with provably
+	// equal points-to sets by hash-based value numbering and merges them
+	// before solving.
+	HVN bool
+	// PtsRep is the representation of points-to sets used while
+	// solving, SliceRep() if nil.
+	PtsRep PtsRep
+	// Parallel is the number of goroutines used to solve.  If it is
+	// less than 2, solving is sequential.  The result does not depend
+	// on Parallel.
+	Parallel int
+}
+
+// DefaultSolveOpts gives the options with which models are created:
+// sequential solving with SliceRep() and without the HVN pre-pass.
+func DefaultSolveOpts() SolveOpts {
+	return SolveOpts{}
+}
 
+// SolveOpts returns the options used by Solve.
+func (mod *Model) SolveOpts() SolveOpts {
+	return mod.solveOpts
+}
+
+// SetSolveOpts sets the options used by Solve.
+func (mod *Model) SetSolveOpts(opts SolveOpts) {
+	mod.solveOpts = opts
+}
+
+// PointsToFor appends to dst the locations which p may point to and returns
+// the result.
+//
+// The appended locations are in increasing order and without duplicates.
+// They include mod.Zero() if p may be nil.  They may be roots or locations
+// inside structured data, such as the result of Field or ArrayIndex applied
+// to a root, as for example when p is the address of a struct field.
+//
+// The result reflects the last call to Solve or SolveIncremental; if mod
+// has not been solved, or if p was generated after the last call, nothing
+// is appended.
+func (mod *Model) PointsToFor(dst []Loc, p Loc) []Loc {
+	if int(p) >= len(mod.pts) {
+		return dst
+	}
+	return append(dst, mod.pts[p]...)
 }
 
 func (mod *Model) PlainEncodeConstraints(w io.Writer) error {
@@ -386,6 +582,7 @@ func (mod *Model) PlainDecodeConstraints(r io.Reader) error {
 	buf := make([]byte, 1)
 	for i := 0; i < N; i++ {
 		c := &constraints[i]
+		c.Index = mod.indexing.Zero().Gen()
 		err = c.PlainDecode(r)
 		if err != nil {
 
@@ -438,9 +635,11 @@ func (mod *Model) PlainDecode(r io.Reader) error {
 		mod.locs = tmp
 	}
 	mod.locs = mod.locs[:N]
+	mod.rels = nil
 	buf := make([]byte, 1)
 	for i := Loc(0); i < N; i++ {
 		p := &mod.locs[i]
+		p.rel = mod.indexing.Zero().Gen()
 		if err = p.PlainDecode(br); err != nil {
 			return err
 		}
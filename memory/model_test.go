@@ -21,6 +21,7 @@ import (
 
 	"github.com/go-air/pal/indexing"
 	"github.com/go-air/pal/typeset"
+	"github.com/go-air/pal/xtruth"
 )
 
 func gp() {
@@ -53,3 +54,42 @@ func TestModel(t *testing.T) {
 
 	//fmt.Printf(plain.String(mdl))
 }
+
+func TestOverlapsEquals(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New())
+	nested := types.NewStruct([]*types.Var{
+		types.NewVar(token.NoPos, nil, "a", pairTy),
+		types.NewVar(token.NoPos, nil, "b", arrTy)},
+		[]string{"", ""})
+	s := mdl.Gen(gp.GoType(nested))
+	o := mdl.Gen(gp.GoType(pairTy))
+	a, b := mdl.Field(s, 0), mdl.Field(s, 1)
+	a1, a2 := mdl.Field(a, 0), mdl.Field(a, 1)
+	b0, b2 := mdl.ArrayIndex(b, 0), mdl.ArrayIndex(b, 2)
+	for _, tc := range []struct {
+		x, y     Loc
+		ovl, eql xtruth.T
+	}{
+		{s, s, xtruth.True, xtruth.True},
+		{s, o, xtruth.False, xtruth.False},
+		{a, b, xtruth.False, xtruth.False},
+		{a1, a2, xtruth.False, xtruth.False},
+		{a1, b0, xtruth.False, xtruth.False},
+		{b0, b2, xtruth.False, xtruth.False},
+		{s, a1, xtruth.True, xtruth.False},
+		{b2, b, xtruth.True, xtruth.False},
+		{a, a2, xtruth.True, xtruth.False},
+		{mdl.Zero(), mdl.Zero(), xtruth.True, xtruth.True},
+		{mdl.Zero(), s, xtruth.False, xtruth.False}} {
+		if got := mdl.Overlaps(tc.x, tc.y); got != tc.ovl {
+			t.Errorf("Overlaps(%d, %d) = %s, expected %s", tc.x, tc.y, got, tc.ovl)
+		}
+		if got := mdl.Overlaps(tc.y, tc.x); got != tc.ovl {
+			t.Errorf("Overlaps(%d, %d) = %s, expected %s", tc.y, tc.x, got, tc.ovl)
+		}
+		if got := mdl.Equals(tc.x, tc.y); got != tc.eql {
+			t.Errorf("Equals(%d, %d) = %s, expected %s", tc.x, tc.y, got, tc.eql)
+		}
+	}
+}
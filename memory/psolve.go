@@ -0,0 +1,243 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"sort"
+	"sync"
+)
+
+// solveParallel is the parallel counterpart of solve, using P goroutines.
+//
+// solveParallel proceeds in rounds.  Each round processes every
+// representative with pending facts, in three phases separated by
+// barriers:
+//
+//  1. the pending facts of the round are applied to the complex
+//     constraints and edges, giving new edges and new facts.
+//  2. the new edges are added to the graph, giving new facts.
+//  3. the new facts are added to the points-to sets.
+//
+// In phases 2 and 3, each loc is owned by one goroutine, which is the only
+// one to modify its successors and points-to set.  Cycles are collapsed
+// between rounds.  Since the least solution is unique, the result is the
+// same as that of solve.
+func (s *solver) solveParallel(P int) {
+	ws := make([]*pworker, P)
+	for i := range ws {
+		ws[i] = &pworker{
+			s:     s,
+			id:    i,
+			P:     P,
+			edges: make([][]pedge, P),
+			facts: make([][]pfact, P)}
+	}
+	front := s.frontier(ws)
+	var wg sync.WaitGroup
+	run := func(f func(w *pworker)) {
+		wg.Add(P)
+		for _, w := range ws {
+			go func(w *pworker) {
+				defer wg.Done()
+				f(w)
+			}(w)
+		}
+		wg.Wait()
+	}
+	for len(front) > 0 {
+		run(func(w *pworker) { w.apply(front) })
+		run(func(w *pworker) { w.addEdges(ws) })
+		run(func(w *pworker) { w.addFacts(ws) })
+		for _, w := range ws {
+			for _, e := range w.pairs {
+				n, m := s.find(e[0]), s.find(e[1])
+				if n == m || s.lcdDone[e] || !s.ptsEqual(n, m) {
+					continue
+				}
+				s.lcdDone[e] = true
+				s.lcdCands = append(s.lcdCands, m)
+			}
+			w.pairs = w.pairs[:0]
+		}
+		if len(s.lcdCands) > 0 {
+			s.collapseCycles()
+		}
+		front = s.frontier(ws)
+	}
+}
+
+// frontier gathers, in increasing order, the representatives with
+// pending facts.
+func (s *solver) frontier(ws []*pworker) []Loc {
+	var res []Loc
+	for _, n := range s.work {
+		s.inWork[n] = false
+		res = append(res, n)
+	}
+	s.work = s.work[:0]
+	for _, w := range ws {
+		for _, n := range w.next {
+			s.inWork[n] = false
+			res = append(res, n)
+		}
+		w.next = w.next[:0]
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	j := 0
+	for i, n := range res {
+		if i > 0 && n == res[i-1] {
+			continue
+		}
+		if s.rep[n] != n || len(s.delta[n]) == 0 {
+			continue
+		}
+		res[j] = n
+		j++
+	}
+	return res[:j]
+}
+
+// findRO is find without path compression, safe to call concurrently
+// when the union find is not modified.
+func (s *solver) findRO(m Loc) Loc {
+	for s.rep[m] != m {
+		m = s.rep[m]
+	}
+	return m
+}
+
+type pedge struct {
+	src, dst Loc
+}
+
+type pfact struct {
+	dst Loc
+	os  locSet
+}
+
+type pworker struct {
+	s     *solver
+	id, P int
+
+	edges [][]pedge // by owner of src
+	facts [][]pfact // by owner of dst
+	pairs [][2]Loc  // propagated edges, for cycle detection
+	next  []Loc     // owned locs with new pending facts
+	offs  []Loc
+}
+
+func (w *pworker) owner(m Loc) int {
+	return int(m) % w.P
+}
+
+func (w *pworker) addEdge(src, dst Loc) {
+	src, dst = w.s.findRO(src), w.s.findRO(dst)
+	if src == dst {
+		return
+	}
+	o := w.owner(src)
+	w.edges[o] = append(w.edges[o], pedge{src: src, dst: dst})
+}
+
+func (w *pworker) addTandemEdges(src, dst Loc) {
+	n := w.s.mod.tandemSize(src, dst)
+	for k := Loc(0); k < n; k++ {
+		w.addEdge(src+k, dst+k)
+	}
+}
+
+func (w *pworker) addFact(dst Loc, os locSet) {
+	dst = w.s.findRO(dst)
+	o := w.owner(dst)
+	w.facts[o] = append(w.facts[o], pfact{dst: dst, os: os})
+}
+
+// apply is phase 1, for the elements of front assigned to w.
+func (w *pworker) apply(front []Loc) {
+	s := w.s
+	mod := s.mod
+	for i := w.id; i < len(front); i += w.P {
+		n := front[i]
+		d := s.delta[n]
+		s.delta[n] = nil
+		for _, ci := range s.loads[n] {
+			c := &mod.constraints[ci]
+			for _, o := range d {
+				w.addTandemEdges(o, c.Dest)
+			}
+		}
+		for _, ci := range s.stores[n] {
+			c := &mod.constraints[ci]
+			for _, o := range d {
+				w.addTandemEdges(c.Src, o)
+			}
+		}
+		for _, ci := range s.xfers[n] {
+			c := &mod.constraints[ci]
+			for _, o := range d {
+				w.offs = mod.offsets(w.offs[:0], o, c.Index)
+				if len(w.offs) > 0 {
+					w.addFact(c.Dest, append(locSet(nil), w.offs...))
+				}
+			}
+		}
+		for _, m := range s.succs[n] {
+			m = s.findRO(m)
+			if m == n {
+				continue
+			}
+			w.addFact(m, d)
+			w.pairs = append(w.pairs, [2]Loc{n, m})
+		}
+	}
+}
+
+// addEdges is phase 2, for the edges whose source is owned by w.
+func (w *pworker) addEdges(ws []*pworker) {
+	s := w.s
+	for _, o := range ws {
+		for _, e := range o.edges[w.id] {
+			if !s.succs[e.src].add(e.dst) {
+				continue
+			}
+			if p := s.pts[e.src]; p != nil && p.Len() > 0 {
+				w.addFact(e.dst, p.AppendTo(nil))
+			}
+		}
+		o.edges[w.id] = o.edges[w.id][:0]
+	}
+}
+
+// addFacts is phase 3, for the facts whose destination is owned by w.
+func (w *pworker) addFacts(ws []*pworker) {
+	s := w.s
+	for _, o := range ws {
+		for _, f := range o.facts[w.id] {
+			pts := s.ptsOf(f.dst)
+			added := false
+			for _, m := range f.os {
+				if pts.Add(m) {
+					s.delta[f.dst].add(m)
+					added = true
+				}
+			}
+			if added && !s.inWork[f.dst] {
+				s.inWork[f.dst] = true
+				w.next = append(w.next, f.dst)
+			}
+		}
+		o.facts[w.id] = o.facts[w.id][:0]
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"fmt"
+	"go/types"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/typeset"
+)
+
+func TestSolveParallel(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		a := randModel(seed, 60, 120)
+		a.SetSolveOpts(SolveOpts{})
+		a.Solve()
+		for _, r := range ptsReps {
+			for _, hvn := range []bool{false, true} {
+				b := randModel(seed, 60, 120)
+				b.SetSolveOpts(SolveOpts{HVN: hvn, PtsRep: r.rep(), Parallel: 4})
+				b.Solve()
+				for i := 0; i < a.Len(); i++ {
+					m := Loc(i)
+					checkPts(t, b, m, a.PointsToFor(nil, m)...)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkSolveParallel(b *testing.B) {
+	for _, p := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("P%d", p), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				mdl := randModel(int64(i), 2000, 4000)
+				mdl.SetSolveOpts(SolveOpts{HVN: true, Parallel: p})
+				b.StartTimer()
+				mdl.Solve()
+			}
+		})
+	}
+}
+
+// relModel builds a model with n pointers into a region of Rel
+// locations which are related by transfers with variable indices.
+func relModel(n int) (mdl *Model, ptrs []Loc) {
+	mdl = NewModel(indexing.ConstVals())
+	idx := mdl.indexing
+	gp := NewGenParams(typeset.New()).Class(Heap)
+	base := mdl.Gen(gp.GoType(types.NewSlice(pairTy)))
+	gp.Class(Local)
+	for i := 0; i < n; i++ {
+		e := mdl.GenRel(gp.GoType(pairTy), base, idx.FromInt64(int64(1+3*i)))
+		p := mdl.Gen(gp.GoType(types.NewPointer(pairTy)))
+		mdl.AddAddressOf(p, e)
+		ptrs = append(ptrs, p)
+	}
+	return mdl, ptrs
+}
+
+// TestSolveParallelImportedRels checks that a parallel incremental solve
+// after an import agrees with a sequential one when variable index
+// transfers reach Rel locs.
+func TestSolveParallelImportedRels(t *testing.T) {
+	seq, sptrs := relModel(32)
+	seq.Solve()
+	par, pptrs := relModel(32)
+	par.SetSolveOpts(SolveOpts{Parallel: 8})
+	par.Solve()
+	for _, mdl := range []*Model{seq, par} {
+		mdl.Import(randModel(1, 20, 40), nil, nil)
+	}
+	for i := range sptrs {
+		for _, c := range [...]struct {
+			mdl  *Model
+			ptrs []Loc
+		}{{seq, sptrs}, {par, pptrs}} {
+			gp := NewGenParams(typeset.New()).Class(Local)
+			q := c.mdl.Gen(gp.GoType(types.NewPointer(pairTy)))
+			c.mdl.AddTransferIndex(q, c.ptrs[i], c.mdl.indexing.Var())
+		}
+	}
+	seq.SolveIncremental()
+	par.SolveIncremental()
+	for i := 0; i < seq.Len(); i++ {
+		m := Loc(i)
+		checkPts(t, par, m, seq.PointsToFor(nil, m)...)
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"golang.org/x/tools/container/intsets"
+)
+
+// PtsSet is a set of Locs, as used by the solver to represent points-to
+// sets.
+type PtsSet interface {
+	// Add adds m to the set, returning whether or not m was not already
+	// present.
+	Add(m Loc) bool
+	// Len returns the number of elements in the set.
+	Len() int
+	// Equals returns whether the set has the same elements as o.  o
+	// has the same representation as the receiver.
+	Equals(o PtsSet) bool
+	// AppendTo appends the elements of the set to dst in increasing
+	// order and returns the result.
+	AppendTo(dst []Loc) []Loc
+}
+
+// PtsRep is a representation of points-to sets.
+type PtsRep interface {
+	// New returns a new empty set.
+	New() PtsSet
+}
+
+// SliceRep represents points-to sets as sorted slices.  It is the
+// default.
+func SliceRep() PtsRep {
+	return sliceRep{}
+}
+
+// SparseRep represents points-to sets as sparse bitsets.
+func SparseRep() PtsRep {
+	return sparseRep{}
+}
+
+// SharedRep represents points-to sets as hash-consed immutable sorted
+// slices, so that equal sets share storage and comparing sets is constant
+// time.  Like a BDD, a SharedRep is a table of sets; sets created by
+// different calls to SharedRep should not be mixed.  Sets may be added to
+// concurrently.
+func SharedRep() PtsRep {
+	return &sharedRep{
+		index: make(map[string]uint32),
+		adds:  make(map[sharedAdd]uint32),
+		sets:  []locSet{nil}}
+}
+
+type sliceRep struct{}
+
+func (sliceRep) New() PtsSet {
+	return &locSet{}
+}
+
+func (s *locSet) Add(m Loc) bool {
+	return s.add(m)
+}
+
+func (s *locSet) Len() int {
+	return len(*s)
+}
+
+func (s *locSet) Equals(o PtsSet) bool {
+	return s.equals(*o.(*locSet))
+}
+
+func (s *locSet) AppendTo(dst []Loc) []Loc {
+	return append(dst, *s...)
+}
+
+type sparseRep struct{}
+
+func (sparseRep) New() PtsSet {
+	return &sparseSet{}
+}
+
+type sparseSet struct {
+	bits intsets.Sparse
+}
+
+func (s *sparseSet) Add(m Loc) bool {
+	return s.bits.Insert(int(m))
+}
+
+func (s *sparseSet) Len() int {
+	return s.bits.Len()
+}
+
+func (s *sparseSet) Equals(o PtsSet) bool {
+	return s.bits.Equals(&o.(*sparseSet).bits)
+}
+
+func (s *sparseSet) AppendTo(dst []Loc) []Loc {
+	for x := s.bits.LowerBound(0); x != intsets.MaxInt; x = s.bits.LowerBound(x + 1) {
+		dst = append(dst, Loc(x))
+	}
+	return dst
+}
+
+type sharedAdd struct {
+	id uint32
+	m  Loc
+}
+
+type sharedRep struct {
+	mu    sync.Mutex
+	sets  []locSet          // by id, immutable
+	index map[string]uint32 // key -> id
+	adds  map[sharedAdd]uint32
+}
+
+func (r *sharedRep) New() PtsSet {
+	return &sharedSet{rep: r}
+}
+
+// add returns the id of the set with id 'id' with m added.
+func (r *sharedRep) add(id uint32, m Loc) uint32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := sharedAdd{id: id, m: m}
+	if res, ok := r.adds[k]; ok {
+		return res
+	}
+	set := append(locSet(nil), r.sets[id]...)
+	res := id
+	if set.add(m) {
+		var tmp [binary.MaxVarintLen32]byte
+		key := make([]byte, 0, len(set)*2)
+		for _, m := range set {
+			n := binary.PutUvarint(tmp[:], uint64(m))
+			key = append(key, tmp[:n]...)
+		}
+		var ok bool
+		res, ok = r.index[string(key)]
+		if !ok {
+			res = uint32(len(r.sets))
+			r.sets = append(r.sets, set)
+			r.index[string(key)] = res
+		}
+	}
+	r.adds[k] = res
+	return res
+}
+
+type sharedSet struct {
+	rep *sharedRep
+	id  uint32
+}
+
+func (s *sharedSet) Add(m Loc) bool {
+	id := s.rep.add(s.id, m)
+	if id == s.id {
+		return false
+	}
+	s.id = id
+	return true
+}
+
+func (s *sharedSet) Len() int {
+	return len(s.rep.sets[s.id])
+}
+
+func (s *sharedSet) Equals(o PtsSet) bool {
+	return s.id == o.(*sharedSet).id
+}
+
+func (s *sharedSet) AppendTo(dst []Loc) []Loc {
+	return append(dst, s.rep.sets[s.id]...)
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"testing"
+)
+
+var ptsReps = []struct {
+	name string
+	rep  func() PtsRep
+}{
+	{"slice", SliceRep},
+	{"sparse", SparseRep},
+	{"shared", SharedRep}}
+
+func TestPtsSet(t *testing.T) {
+	for _, r := range ptsReps {
+		rep := r.rep()
+		a, b := rep.New(), rep.New()
+		for _, m := range []Loc{7, 3, 200000, 3} {
+			a.Add(m)
+		}
+		if a.Add(7) {
+			t.Errorf("%s: re-added 7", r.name)
+		}
+		if a.Len() != 3 {
+			t.Errorf("%s: len %d", r.name, a.Len())
+		}
+		got := a.AppendTo(nil)
+		if len(got) != 3 || got[0] != 3 || got[1] != 7 || got[2] != 200000 {
+			t.Errorf("%s: elems %v", r.name, got)
+		}
+		if a.Equals(b) {
+			t.Errorf("%s: equal to empty", r.name)
+		}
+		for _, m := range []Loc{200000, 7, 3} {
+			b.Add(m)
+		}
+		if !a.Equals(b) {
+			t.Errorf("%s: not equal", r.name)
+		}
+	}
+}
+
+func TestSolvePtsReps(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		a := randModel(seed, 40, 80)
+		a.Solve()
+		for _, r := range ptsReps[1:] {
+			b := randModel(seed, 40, 80)
+			b.SetSolveOpts(SolveOpts{HVN: true, PtsRep: r.rep()})
+			b.Solve()
+			for i := 0; i < a.Len(); i++ {
+				m := Loc(i)
+				checkPts(t, b, m, a.PointsToFor(nil, m)...)
+			}
+		}
+	}
+}
+
+func BenchmarkSolvePtsReps(b *testing.B) {
+	for _, r := range ptsReps {
+		b.Run(r.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				mdl := randModel(int64(i), 2000, 4000)
+				mdl.SetSolveOpts(SolveOpts{HVN: true, PtsRep: r.rep()})
+				b.StartTimer()
+				mdl.Solve()
+			}
+		})
+	}
+}
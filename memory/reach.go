@@ -0,0 +1,51 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import "sort"
+
+// Reachable appends to dst the roots of the regions reachable from the
+// region of m by following the points-to relation, in increasing
+// order, and returns the result.  The root of m is in the result only
+// if it is reachable from itself.  The nil location is never in the
+// result.
+func (mod *Model) Reachable(dst []Loc, m Loc) []Loc {
+	if !mod.solved() {
+		mod.SolveIncremental()
+	}
+	seen := make(map[Loc]bool)
+	start := len(dst)
+	work := append(make([]Loc, 0, 32), mod.locs[m].root)
+	visit := func(o Loc) {
+		r := mod.locs[o].root
+		if seen[r] || r == mod.Zero() {
+			return
+		}
+		seen[r] = true
+		dst = append(dst, r)
+		work = append(work, r)
+	}
+	for len(work) > 0 {
+		r := work[len(work)-1]
+		work = work[:len(work)-1]
+		mod.keepPointees(r, visit)
+		for _, rel := range mod.relsOf(r) {
+			mod.keepPointees(rel, visit)
+		}
+	}
+	res := dst[start:]
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return dst
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/typeset"
+)
+
+func TestReachable(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New())
+	ppTy := types.NewPointer(intPtrTy)
+	x := mdl.Gen(gp.Class(Heap).GoType(types.Typ[types.Int]))
+	y := mdl.Gen(gp.Class(Heap).GoType(intPtrTy))
+	z := mdl.Gen(gp.Class(Heap).GoType(types.Typ[types.Int]))
+	p := mdl.Gen(gp.Class(Local).GoType(ppTy))
+	q := mdl.Gen(gp.Class(Local).GoType(intPtrTy))
+	mdl.AddAddressOf(p, y)
+	mdl.AddAddressOf(y, x)
+	mdl.AddAddressOf(q, z)
+	mdl.AddAddressOf(q, mdl.Zero())
+
+	got := mdl.Reachable(nil, p)
+	if len(got) != 2 || got[0] != x || got[1] != y {
+		t.Errorf("reachable from p: %v, expected [%d %d]", got, x, y)
+	}
+	got = mdl.Reachable(got[:0], q)
+	if len(got) != 1 || got[0] != z {
+		t.Errorf("reachable from q: %v, expected [%d]", got, z)
+	}
+	mdl.AddAddressOf(x, p)
+	got = mdl.Reachable(nil, p)
+	if len(got) != 3 || got[2] != p {
+		t.Errorf("cycle: %v", got)
+	}
+}
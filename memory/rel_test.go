@@ -0,0 +1,88 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/internal/plain"
+	"github.com/go-air/pal/typeset"
+	"github.com/go-air/pal/xtruth"
+)
+
+func TestRel(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	idx := mdl.indexing
+	gp := NewGenParams(typeset.New()).Class(Heap)
+	base := mdl.Gen(gp.GoType(types.NewSlice(pairTy)))
+	e1 := mdl.GenRel(gp.GoType(pairTy), base, idx.FromInt64(1))
+	e4 := mdl.GenRel(gp.GoType(pairTy), base, idx.FromInt64(4))
+	if mdl.locs[e1].class != Rel || mdl.locs[e1+1].class != Rel {
+		t.Errorf("class not rel")
+	}
+	if mdl.Parent(e1) != base || mdl.Root(e1+2) != base || mdl.Parent(e1+2) != e1 {
+		t.Errorf("rel structure")
+	}
+	if mdl.Overlaps(e1, e4) != xtruth.False || mdl.Overlaps(e1, e1+1) != xtruth.True {
+		t.Errorf("rel overlaps")
+	}
+	if mdl.Overlaps(base, e1) != xtruth.False {
+		t.Errorf("rel overlaps base")
+	}
+
+	gp.Class(Local)
+	p := mdl.Gen(gp.GoType(types.NewPointer(pairTy)))
+	next := mdl.Gen(gp.GoType(types.NewPointer(pairTy)))
+	any := mdl.Gen(gp.GoType(types.NewPointer(pairTy)))
+	fld := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddAddressOf(p, e1)
+	mdl.AddTransferIndex(next, p, idx.FromInt64(3))
+	mdl.AddTransferIndex(any, p, idx.Var())
+	mdl.AddTransferIndex(fld, any, idx.FromInt64(2))
+	mdl.Solve()
+	checkPts(t, mdl, next, e4)
+	checkPts(t, mdl, any, e1, e4)
+	checkPts(t, mdl, fld, e1+2, e4+2)
+
+	m := mdl.locs[e4+1]
+	if err := plain.TestRoundTrip(&m, false); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := idx.ToInt64(m.rel); !ok || v != 5 || m.class != Rel {
+		t.Errorf("rel decode %s", plain.String(&m))
+	}
+}
+
+func TestRelSummary(t *testing.T) {
+	mdl := NewModel(indexing.Symbolic())
+	idx := mdl.indexing
+	gp := NewGenParams(typeset.New()).Class(Heap)
+	base := mdl.Gen(gp.GoType(types.NewSlice(pairTy)))
+	// the elements at any index.
+	e := mdl.GenRel(gp.GoType(pairTy), base, idx.Var())
+
+	gp.Class(Local)
+	p := mdl.Gen(gp.GoType(types.NewPointer(pairTy)))
+	next := mdl.Gen(gp.GoType(types.NewPointer(pairTy)))
+	mid := mdl.Gen(gp.GoType(types.NewPointer(pairTy)))
+	mdl.AddAddressOf(p, e)
+	mdl.AddTransferIndex(next, p, idx.FromInt64(3))
+	mdl.AddTransferIndex(mid, p, idx.FromInt64(4))
+	mdl.Solve()
+	checkPts(t, mdl, next, e)
+	checkPts(t, mdl, mid)
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+// sccState holds the state of Tarjan's algorithm, run over the
+// representatives of the solver's constraint graph.
+type sccState struct {
+	index   map[Loc]int
+	low     map[Loc]int
+	onStack map[Loc]bool
+	stack   []Loc
+	frames  []sccFrame
+	n       int
+}
+
+type sccFrame struct {
+	m Loc
+	i int // next successor of m to visit
+}
+
+// collapseCycles finds the strongly connected components reachable from
+// the lazy cycle detection candidates and merges each one with more than
+// one node.
+func (s *solver) collapseCycles() {
+	st := &s.scc
+	st.index = make(map[Loc]int)
+	st.low = make(map[Loc]int)
+	st.onStack = make(map[Loc]bool)
+	st.n = 0
+	for _, m := range s.lcdCands {
+		m = s.find(m)
+		if _, ok := st.index[m]; ok {
+			continue
+		}
+		s.tarjan(m)
+	}
+	s.lcdCands = s.lcdCands[:0]
+}
+
+// tarjan visits the graph from m iteratively, collapsing the components
+// found.
+func (s *solver) tarjan(m Loc) {
+	st := &s.scc
+	visit := func(m Loc) {
+		st.n++
+		st.index[m] = st.n
+		st.low[m] = st.n
+		st.stack = append(st.stack, m)
+		st.onStack[m] = true
+		st.frames = append(st.frames, sccFrame{m: m})
+	}
+	visit(m)
+	for len(st.frames) > 0 {
+		f := &st.frames[len(st.frames)-1]
+		n := f.m
+		if f.i < len(s.succs[n]) {
+			w := s.find(s.succs[n][f.i])
+			f.i++
+			if w == n {
+				continue
+			}
+			if _, ok := st.index[w]; !ok {
+				visit(w)
+				continue
+			}
+			if st.onStack[w] && st.index[w] < st.low[n] {
+				st.low[n] = st.index[w]
+			}
+			continue
+		}
+		st.frames = st.frames[:len(st.frames)-1]
+		if len(st.frames) > 0 {
+			p := st.frames[len(st.frames)-1].m
+			if st.low[n] < st.low[p] {
+				st.low[p] = st.low[n]
+			}
+		}
+		if st.low[n] != st.index[n] {
+			continue
+		}
+		// n is the root of a component.
+		i := len(st.stack) - 1
+		for st.stack[i] != n {
+			i--
+		}
+		comp := st.stack[i:]
+		for _, w := range comp {
+			st.onStack[w] = false
+		}
+		if len(comp) > 1 {
+			r := comp[0]
+			for _, w := range comp[1:] {
+				r = s.unify(r, w)
+			}
+		}
+		st.stack = st.stack[:i]
+	}
+}
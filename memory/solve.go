@@ -0,0 +1,549 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/xtruth"
+)
+
+// solver computes the least points-to relation satisfying the constraints
+// of a Model.
+//
+// The solver is a worklist algorithm with difference propagation.  The
+// constraints are turned into a graph whose nodes are Locs.  An edge m -> n
+// indicates pts(m) ⊆ pts(n).  Plain transfers give edges directly, loads,
+// stores and indexed transfers are "complex" constraints which add edges
+// or points-to facts as the points-to set of their pointer operand grows.
+//
+// Cycles in the graph are detected lazily and collapsed: when propagation
+// along an edge m -> n finds pts(m) == pts(n), the solver looks for a
+// strongly connected component containing n, and merges the nodes of any
+// such component into one representative.  Merging only identifies points-to
+// sets, it does not change the locations themselves, so loc arithmetic such
+// as descending structured data and offsets is unaffected.
+type solver struct {
+	mod    *Model
+	rep    []Loc // union find, rep[m] == m for representatives
+	ptsRep PtsRep
+	pts    []PtsSet // the solution, for representatives
+	delta  []locSet // facts in pts not yet propagated
+	succs  []locSet // the edges, not necessarily between representatives
+
+	loads  [][]int // loads[m]: indices of constraints 'd = *m'
+	stores [][]int // stores[m]: indices of constraints '*m = s'
+	xfers  [][]int // xfers[m]: indices of constraints 'd = m + i', i != 0
+
+	work   []Loc
+	inWork []bool
+	offs   []Loc // scratch for offsets
+	elts   []Loc // scratch for elements of points-to sets
+
+	// lazy cycle detection
+	lcdDone  map[[2]Loc]bool // edges already checked
+	lcdCands []Loc           // candidates to check
+	scc      sccState
+
+	// incremental solving
+	nCons     int      // number of constraints added
+	hvnMerged []bool   // locs merged by hvn, nil if hvn was not run
+	adrTaken  []bool   // roots whose address is taken, as seen by hvn
+	res       []locSet // the last result
+}
+
+func newSolver(mod *Model) *solver {
+	N := len(mod.locs)
+	s := &solver{
+		mod:     mod,
+		ptsRep:  mod.solveOpts.PtsRep,
+		rep:     make([]Loc, N),
+		pts:     make([]PtsSet, N),
+		delta:   make([]locSet, N),
+		succs:   make([]locSet, N),
+		loads:   make([][]int, N),
+		stores:  make([][]int, N),
+		xfers:   make([][]int, N),
+		inWork:  make([]bool, N),
+		lcdDone: make(map[[2]Loc]bool)}
+	if s.ptsRep == nil {
+		s.ptsRep = SliceRep()
+	}
+	for i := range s.rep {
+		s.rep[i] = Loc(i)
+	}
+	return s
+}
+
+// grow extends s to the locations of s.mod generated since s was created
+// or last grown, and returns whether or not s can soundly be used to solve
+// the constraints added since.
+func (s *solver) grow() bool {
+	mod := s.mod
+	N := len(mod.locs)
+	if N < len(s.rep) || s.nCons > len(mod.constraints) {
+		return false
+	}
+	for i := len(s.rep); i < N; i++ {
+		s.rep = append(s.rep, Loc(i))
+		s.pts = append(s.pts, nil)
+		s.delta = append(s.delta, nil)
+		s.succs = append(s.succs, nil)
+		s.loads = append(s.loads, nil)
+		s.stores = append(s.stores, nil)
+		s.xfers = append(s.xfers, nil)
+		s.inWork = append(s.inWork, false)
+		if s.hvnMerged != nil {
+			s.hvnMerged = append(s.hvnMerged, false)
+			s.adrTaken = append(s.adrTaken, false)
+		}
+	}
+	if s.hvnMerged == nil {
+		return true
+	}
+	merged := func(m, n Loc) bool {
+		for ; m < n; m++ {
+			if s.hvnMerged[m] {
+				return true
+			}
+		}
+		return false
+	}
+	region := func(m Loc) Loc {
+		if sz := mod.locs[m].lsz; sz > 0 {
+			return m + Loc(sz)
+		}
+		return m + 1
+	}
+	for i := s.nCons; i < len(mod.constraints); i++ {
+		c := &mod.constraints[i]
+		if c.Dest == NoLoc || c.Src == NoLoc {
+			continue
+		}
+		switch c.Kind {
+		case KAddressOf:
+			if s.hvnMerged[c.Dest] {
+				return false
+			}
+			r := mod.locs[c.Src].root
+			if !s.adrTaken[r] {
+				s.adrTaken[r] = true
+				if merged(r, region(r)) {
+					return false
+				}
+			}
+		case KLoad:
+			if merged(c.Dest, region(c.Dest)) {
+				return false
+			}
+		case KTransfer:
+			n := Loc(1)
+			if s.isZero(c.Index) {
+				n = mod.tandemSize(c.Src, c.Dest)
+			}
+			if merged(c.Dest, c.Dest+n) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// addConstraints adds the constraints of s.mod which have not yet been
+// added to s.
+func (s *solver) addConstraints() {
+	mod := s.mod
+	for i := s.nCons; i < len(mod.constraints); i++ {
+		c := &mod.constraints[i]
+		if c.Dest == NoLoc || c.Src == NoLoc {
+			// constants and the like.
+			continue
+		}
+		switch c.Kind {
+		case KAddressOf:
+			s.addPts(c.Dest, c.Src)
+		case KLoad:
+			r := s.find(c.Src)
+			s.loads[r] = append(s.loads[r], i)
+			s.refresh(r)
+		case KStore:
+			r := s.find(c.Dest)
+			s.stores[r] = append(s.stores[r], i)
+			s.refresh(r)
+		case KTransfer:
+			if s.isZero(c.Index) {
+				s.addTandemEdges(c.Src, c.Dest)
+				continue
+			}
+			r := s.find(c.Src)
+			s.xfers[r] = append(s.xfers[r], i)
+			s.refresh(r)
+		}
+	}
+	s.nCons = len(mod.constraints)
+}
+
+func (s *solver) solve() {
+	mod := s.mod
+	for len(s.work) > 0 {
+		n := s.work[0]
+		s.work = s.work[1:]
+		s.inWork[n] = false
+		if s.rep[n] != n {
+			// merged, delta moved to rep.
+			continue
+		}
+		d := s.delta[n]
+		s.delta[n] = nil
+		if len(d) == 0 {
+			continue
+		}
+		for _, ci := range s.loads[n] {
+			c := &mod.constraints[ci]
+			for _, o := range d {
+				s.addTandemEdges(o, c.Dest)
+			}
+		}
+		for _, ci := range s.stores[n] {
+			c := &mod.constraints[ci]
+			for _, o := range d {
+				s.addTandemEdges(c.Src, o)
+			}
+		}
+		for _, ci := range s.xfers[n] {
+			c := &mod.constraints[ci]
+			for _, o := range d {
+				s.offs = mod.offsets(s.offs[:0], o, c.Index)
+				for _, t := range s.offs {
+					s.addPts(c.Dest, t)
+				}
+			}
+		}
+		for _, m := range s.succs[n] {
+			m = s.find(m)
+			if m == n {
+				continue
+			}
+			s.addAll(m, d)
+			if s.ptsEqual(m, n) {
+				e := [2]Loc{n, m}
+				if !s.lcdDone[e] {
+					s.lcdDone[e] = true
+					s.lcdCands = append(s.lcdCands, m)
+				}
+			}
+		}
+		if len(s.lcdCands) > 0 {
+			s.collapseCycles()
+		}
+	}
+}
+
+// refresh schedules the whole points-to set of the representative r for
+// propagation, as when a complex constraint is added to r after solving.
+func (s *solver) refresh(r Loc) {
+	if es := s.elems(r); len(es) > len(s.delta[r]) {
+		s.delta[r] = append(s.delta[r][:0], es...)
+		s.push(r)
+	}
+}
+
+// result places the solution in a form indexed by all locs, rather than
+// only by representatives.
+//
+// Since points-to sets only grow, only the representatives whose points-to
+// set size changed since the last call are recomputed.
+func (s *solver) result() []locSet {
+	N := len(s.pts)
+	res := s.res
+	if len(res) < N {
+		res = append(res, make([]locSet, N-len(res))...)
+	}
+	for i := range s.pts {
+		p := s.pts[i]
+		if s.rep[i] != Loc(i) || p == nil {
+			continue
+		}
+		if p.Len() != len(res[i]) {
+			res[i] = p.AppendTo(nil)
+		}
+	}
+	for i := range res {
+		res[i] = res[s.find(Loc(i))]
+	}
+	s.res = res
+	return res
+}
+
+func (s *solver) ptsEqual(a, b Loc) bool {
+	pa, pb := s.pts[a], s.pts[b]
+	switch {
+	case pa == nil:
+		return pb == nil || pb.Len() == 0
+	case pb == nil:
+		return pa.Len() == 0
+	}
+	return pa.Len() == pb.Len() && pa.Equals(pb)
+}
+
+// elems returns the elements of the points-to set of the representative m,
+// in a scratch buffer.
+func (s *solver) elems(m Loc) locSet {
+	s.elts = s.elts[:0]
+	if p := s.pts[m]; p != nil {
+		s.elts = p.AppendTo(s.elts)
+	}
+	return s.elts
+}
+
+func (s *solver) find(m Loc) Loc {
+	for s.rep[m] != m {
+		s.rep[m] = s.rep[s.rep[m]]
+		m = s.rep[m]
+	}
+	return m
+}
+
+// unify merges the representatives a and b.
+func (s *solver) unify(a, b Loc) Loc {
+	if a == b {
+		return a
+	}
+	if b < a {
+		a, b = b, a
+	}
+	s.rep[b] = a
+	s.addAll(a, s.elems(b))
+	s.pts[b], s.delta[b] = nil, nil
+	for _, m := range s.succs[b] {
+		s.succs[a].add(m)
+	}
+	s.succs[b] = nil
+	s.loads[a] = append(s.loads[a], s.loads[b]...)
+	s.stores[a] = append(s.stores[a], s.stores[b]...)
+	s.xfers[a] = append(s.xfers[a], s.xfers[b]...)
+	s.loads[b], s.stores[b], s.xfers[b] = nil, nil, nil
+	// the successors and complex constraints of a and b must see all of
+	// the merged points-to set, not only the facts pending for a and b.
+	s.refresh(a)
+	return a
+}
+
+func (s *solver) isZero(i indexing.I) bool {
+	v, ok := s.mod.indexing.ToInt64(i)
+	return ok && v == 0
+}
+
+// addTandemEdges adds edges src+k -> dst+k, descending the structured
+// data at src and dst in tandem.
+func (s *solver) addTandemEdges(src, dst Loc) {
+	n := s.mod.tandemSize(src, dst)
+	for k := Loc(0); k < n; k++ {
+		s.addEdge(src+k, dst+k)
+	}
+}
+
+func (s *solver) addEdge(src, dst Loc) {
+	src, dst = s.find(src), s.find(dst)
+	if src == dst {
+		return
+	}
+	if !s.succs[src].add(dst) {
+		return
+	}
+	s.addAll(dst, s.elems(src))
+}
+
+func (s *solver) ptsOf(m Loc) PtsSet {
+	p := s.pts[m]
+	if p == nil {
+		p = s.ptsRep.New()
+		s.pts[m] = p
+	}
+	return p
+}
+
+func (s *solver) addPts(m, o Loc) {
+	m = s.find(m)
+	if !s.ptsOf(m).Add(o) {
+		return
+	}
+	s.delta[m].add(o)
+	s.push(m)
+}
+
+func (s *solver) addAll(m Loc, os locSet) {
+	m = s.find(m)
+	if len(os) == 0 {
+		return
+	}
+	pts := s.ptsOf(m)
+	added := false
+	for _, o := range os {
+		if pts.Add(o) {
+			s.delta[m].add(o)
+			added = true
+		}
+	}
+	if added {
+		s.push(m)
+	}
+}
+
+func (s *solver) push(m Loc) {
+	if s.inWork[m] {
+		return
+	}
+	s.inWork[m] = true
+	s.work = append(s.work, m)
+}
+
+// tandemSize gives the number of locs which can be
+// traversed in tandem starting at a and b.
+func (mod *Model) tandemSize(a, b Loc) Loc {
+	n := mod.locs[a].lsz
+	if m := mod.locs[b].lsz; m < n {
+		n = m
+	}
+	return Loc(n)
+}
+
+// offsets appends to dst the locs which may result from adding i to a
+// pointer to o, and returns the result.
+//
+// When i is a constant, the result is the loc at logical offset i from o,
+// if it is in the same region as o.  The region of a Rel loc is that of its
+// topmost Rel ancestor.  Otherwise, the result consists of the siblings of
+// o (the locs with the same parent as o) whose offset relative to o may
+// equal i, together with the Rel locs whose parent is the parent of o (or o
+// if o is a root) and whose offset may equal that of o plus i.  nil plus
+// anything is nil.
+func (mod *Model) offsets(dst []Loc, o Loc, i indexing.I) []Loc {
+	om := &mod.locs[o]
+	if om.class == Zero {
+		return append(dst, o)
+	}
+	idx := mod.indexing
+	p := om.parent
+	if c, ok := idx.ToInt64(i); ok {
+		// region of o: that of the root, or of the top Rel loc.
+		r := om.root
+		if om.class == Rel {
+			r = o
+			for mod.locs[mod.locs[r].parent].class == Rel {
+				r = mod.locs[r].parent
+			}
+		}
+		v := int64(o) + c
+		if v >= int64(r) && v < int64(r)+int64(mod.locs[r].lsz) {
+			return append(dst, Loc(v))
+		}
+	} else if p == o {
+		if idx.Equal(i, idx.Zero()) != xtruth.False {
+			dst = append(dst, o)
+		}
+	} else {
+		end := p + Loc(mod.locs[p].lsz)
+		for c := p + 1; c < end; c += Loc(mod.locs[c].lsz) {
+			if idx.Equal(i, mod.diff(c, o)) != xtruth.False {
+				dst = append(dst, c)
+			}
+			if mod.locs[c].lsz == 0 {
+				// not structured, avoid looping.
+				break
+			}
+		}
+	}
+	rels := mod.relsOf(p)
+	if len(rels) == 0 {
+		return dst
+	}
+	target := idx.Plus(mod.offset(o), i)
+	for _, w := range rels {
+		if idx.Equal(mod.offset(w), target) != xtruth.False || mod.summarises(w, target) {
+			dst = append(dst, w)
+		}
+	}
+	return dst
+}
+
+// summarises returns whether the Rel loc w, whose offset is not a
+// constant, may represent the element at offset i.
+//
+// Such a loc may represent elements at several offsets, in steps of its
+// size, for example the elements of a slice appended to in a loop.  So
+// it may represent the element at i if i differs from its offset by a
+// multiple of its size, even if the index domain knows that the
+// offsets are not equal.
+func (mod *Model) summarises(w Loc, i indexing.I) bool {
+	idx := mod.indexing
+	off := mod.offset(w)
+	if _, ok := idx.ToInt64(off); ok {
+		return false
+	}
+	d, ok := idx.ToInt64(idx.Plus(i, idx.Times(idx.FromInt64(-1), off)))
+	if !ok {
+		return false
+	}
+	sz := int64(mod.locs[w].lsz)
+	return sz > 0 && d%sz == 0
+}
+
+// diff gives the offset of a relative to b.
+func (mod *Model) diff(a, b Loc) indexing.I {
+	if mod.locs[a].class != Rel && mod.locs[b].class != Rel {
+		return mod.indexing.FromInt64(int64(a) - int64(b))
+	}
+	idx := mod.indexing
+	return idx.Plus(mod.offset(a), idx.Times(idx.FromInt64(-1), mod.offset(b)))
+}
+
+// locSet is a sorted set of Locs.
+type locSet []Loc
+
+// add adds m to s, returning whether or not
+// m was not already present.
+func (s *locSet) add(m Loc) bool {
+	ms := *s
+	n := len(ms)
+	i, j := 0, n
+	for i < j {
+		h := (i + j) / 2
+		if ms[h] < m {
+			i = h + 1
+		} else {
+			j = h
+		}
+	}
+	if i < n && ms[i] == m {
+		return false
+	}
+	ms = append(ms, NoLoc)
+	copy(ms[i+1:], ms[i:n])
+	ms[i] = m
+	*s = ms
+	return true
+}
+
+func (s locSet) equals(o locSet) bool {
+	if len(s) != len(o) {
+		return false
+	}
+	for i := range s {
+		if s[i] != o[i] {
+			return false
+		}
+	}
+	return true
+}
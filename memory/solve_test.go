@@ -0,0 +1,218 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/typeset"
+)
+
+var (
+	intPtrTy = types.NewPointer(types.Typ[types.Int])
+	pairTy   = types.NewStruct([]*types.Var{
+		types.NewVar(token.NoPos, nil, "f1", intPtrTy),
+		types.NewVar(token.NoPos, nil, "f2", intPtrTy)},
+		[]string{"", ""})
+	arrTy = types.NewArray(intPtrTy, 3)
+)
+
+func checkPts(t *testing.T, mdl *Model, p Loc, exp ...Loc) {
+	t.Helper()
+	got := mdl.PointsToFor(nil, p)
+	if len(got) != len(exp) {
+		t.Errorf("pts(%d) = %v, expected %v", p, got, exp)
+		return
+	}
+	for i := range exp {
+		if got[i] != exp[i] {
+			t.Errorf("pts(%d) = %v, expected %v", p, got, exp)
+			return
+		}
+	}
+}
+
+func TestSolveTransfer(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New()).Class(Local)
+	o, p := mdl.WithPointer(gp.GoType(types.Typ[types.Int]))
+	q := mdl.Gen(gp.GoType(intPtrTy))
+	r := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddTransfer(q, p)
+	mdl.AddTransfer(r, q)
+	mdl.AddTransfer(q, r)
+	mdl.Solve()
+	checkPts(t, mdl, p, o)
+	checkPts(t, mdl, q, o)
+	checkPts(t, mdl, r, o)
+	checkPts(t, mdl, mdl.Zero(), mdl.Zero())
+}
+
+func TestSolveLoadStore(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New()).Class(Local)
+	x := mdl.Gen(gp.GoType(types.Typ[types.Int]))
+	y := mdl.Gen(gp.GoType(types.Typ[types.Int]))
+	p := mdl.Gen(gp.GoType(intPtrTy))
+	q := mdl.Gen(gp.GoType(intPtrTy))
+	pp := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	d := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddAddressOf(p, x)
+	mdl.AddAddressOf(q, y)
+	mdl.AddAddressOf(pp, p)
+	mdl.AddStore(pp, q) // *pp = q
+	mdl.AddLoad(d, pp)  // d = *pp
+	mdl.Solve()
+	checkPts(t, mdl, p, x, y)
+	checkPts(t, mdl, d, x, y)
+	checkPts(t, mdl, q, y)
+}
+
+func TestSolveStruct(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New()).Class(Local)
+	x := mdl.Gen(gp.GoType(types.Typ[types.Int]))
+	y := mdl.Gen(gp.GoType(types.Typ[types.Int]))
+	s, ps := mdl.WithPointer(gp.GoType(pairTy))
+	mdl.AddAddressOf(mdl.Field(s, 0), x)
+	mdl.AddAddressOf(mdl.Field(s, 1), y)
+	d := mdl.Gen(gp.GoType(pairTy))
+	mdl.AddLoad(d, ps)
+
+	// &ps.f2, field offset as logical offset
+	f2 := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	mdl.AddTransferIndex(f2, ps, mdl.indexing.FromInt64(2))
+	e := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddLoad(e, f2)
+
+	mdl.Solve()
+	checkPts(t, mdl, mdl.Field(d, 0), x)
+	checkPts(t, mdl, mdl.Field(d, 1), y)
+	checkPts(t, mdl, f2, mdl.Field(s, 1))
+	checkPts(t, mdl, e, y)
+}
+
+func TestSolveArray(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New()).Class(Local)
+	a := mdl.Gen(gp.GoType(arrTy))
+	var xs [3]Loc
+	for i := range xs {
+		xs[i] = mdl.Gen(gp.GoType(types.Typ[types.Int]))
+		mdl.AddAddressOf(mdl.ArrayIndex(a, i), xs[i])
+	}
+	pa := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	mdl.AddAddressOf(pa, mdl.ArrayIndex(a, 0))
+	pv := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	mdl.AddTransferIndex(pv, pa, mdl.indexing.Var())
+	pc := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	mdl.AddTransferIndex(pc, pa, mdl.indexing.FromInt64(2))
+	po := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	mdl.AddTransferIndex(po, pa, mdl.indexing.FromInt64(3))
+	dv := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddLoad(dv, pv)
+	dc := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddLoad(dc, pc)
+
+	mdl.Solve()
+	checkPts(t, mdl, pv, mdl.ArrayIndex(a, 0), mdl.ArrayIndex(a, 1), mdl.ArrayIndex(a, 2))
+	checkPts(t, mdl, pc, mdl.ArrayIndex(a, 2))
+	checkPts(t, mdl, po)
+	checkPts(t, mdl, dv, xs[0], xs[1], xs[2])
+	checkPts(t, mdl, dc, xs[2])
+}
+
+func TestSolveIntervals(t *testing.T) {
+	mdl := NewModel(indexing.Intervals())
+	idx := mdl.indexing
+	gp := NewGenParams(typeset.New()).Class(Local)
+	a := mdl.Gen(gp.GoType(types.NewArray(intPtrTy, 8)))
+	pa := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	mdl.AddAddressOf(pa, mdl.ArrayIndex(a, 0))
+	pm := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	mdl.AddTransferIndex(pm, pa, idx.Band(idx.Var(), idx.FromInt64(3)))
+	mdl.Solve()
+	checkPts(t, mdl, pm, mdl.ArrayIndex(a, 0), mdl.ArrayIndex(a, 1), mdl.ArrayIndex(a, 2), mdl.ArrayIndex(a, 3))
+}
+
+func TestPointsToFor(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New()).Class(Local)
+	s, ps := mdl.WithPointer(gp.GoType(pairTy))
+	p := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	mdl.AddTransferIndex(p, ps, mdl.indexing.FromInt64(1))
+	mdl.AddAddressOf(p, mdl.Zero())
+	if pts := mdl.PointsToFor(nil, p); len(pts) != 0 {
+		t.Errorf("unsolved: got %v", pts)
+	}
+	mdl.Solve()
+	dst := []Loc{s}
+	dst = mdl.PointsToFor(dst, p)
+	if len(dst) != 3 || dst[0] != s || dst[1] != mdl.Zero() || dst[2] != mdl.Field(s, 0) {
+		t.Errorf("got %v", dst)
+	}
+	q := mdl.Gen(gp.GoType(intPtrTy))
+	if pts := mdl.PointsToFor(nil, q); len(pts) != 0 {
+		t.Errorf("generated after solve: got %v", pts)
+	}
+}
+
+func TestSolveCycle(t *testing.T) {
+	mdl := NewModel(indexing.ConstVals())
+	gp := NewGenParams(typeset.New()).Class(Local)
+	x := mdl.Gen(gp.GoType(types.Typ[types.Int]))
+	y := mdl.Gen(gp.GoType(types.Typ[types.Int]))
+	a := mdl.Gen(gp.GoType(pairTy))
+	b := mdl.Gen(gp.GoType(pairTy))
+	mdl.AddTransfer(a, b)
+	mdl.AddTransfer(b, a)
+	mdl.AddAddressOf(mdl.Field(a, 0), x)
+	mdl.AddAddressOf(mdl.Field(b, 1), y)
+
+	// p -> q -> r -> p, with a load from r
+	px := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddAddressOf(px, x)
+	p := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	q := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	r := mdl.Gen(gp.GoType(types.NewPointer(intPtrTy)))
+	mdl.AddAddressOf(p, px)
+	mdl.AddTransfer(q, p)
+	mdl.AddTransfer(r, q)
+	mdl.AddTransfer(p, r)
+	d := mdl.Gen(gp.GoType(intPtrTy))
+	mdl.AddLoad(d, r)
+
+	s := newSolver(mdl)
+	s.addConstraints()
+	s.solve()
+	if s.find(p) != s.find(q) || s.find(q) != s.find(r) {
+		t.Errorf("cycle p q r not collapsed")
+	}
+	if s.find(mdl.Field(a, 0)) == s.find(mdl.Field(a, 1)) {
+		t.Errorf("fields merged")
+	}
+	mdl.pts = s.result()
+	for _, m := range []Loc{a, b} {
+		checkPts(t, mdl, mdl.Field(m, 0), x)
+		checkPts(t, mdl, mdl.Field(m, 1), y)
+	}
+	for _, m := range []Loc{p, q, r} {
+		checkPts(t, mdl, m, px)
+	}
+	checkPts(t, mdl, d, x)
+}
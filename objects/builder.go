@@ -24,6 +24,7 @@ import (
 	"github.com/go-air/pal/internal/plain"
 	"github.com/go-air/pal/memory"
 	"github.com/go-air/pal/typeset"
+	"github.com/go-air/pal/xtruth"
 )
 
 // Builder is a type which supports coordinating
@@ -130,18 +131,34 @@ func (b *Builder) Slice(gty *types.Slice, length, capacity indexing.I) *Slice {
 	sl := b.omap[m].(*Slice)
 	sl.Len = length
 	sl.Cap = capacity
-	b.AddSlot(sl, b.indexing.Zero())
+	b.AddSlot(sl, b.indexing.Var())
 	return sl
 }
 
+// AddSlot adds a slot for the elements of slice at index i.
+//
+// The slot object is a memory.Rel location relative to the backing
+// store of the slice, generated with the first slot.
 func (b *Builder) AddSlot(slice *Slice, i indexing.I) {
 	elemTy := b.ts.Elem(slice.typ)
 	ptrTy := b.ts.PointerTo(elemTy)
+	var base memory.Loc
+	if len(slice.slots) == 0 {
+		base = b.Type(slice.typ).Gen()
+	} else {
+		base = b.mmod.Root(slice.slots[0].Obj)
+	}
+	idx := b.indexing
+	esz := idx.FromInt64(int64(b.ts.Lsize(elemTy)))
+	off := idx.Times(i, esz)
 	ptr := b.Type(ptrTy).Gen()
-	obj := b.Type(elemTy).Gen()
+	obj := b.mmod.GenRel(b.mgp.Type(elemTy), base, idx.Plus(idx.One(), off))
 	b.walkObj(obj)
-	b.AddTransferIndex(ptr, slice.loc, i)
+	b.AddTransferIndex(ptr, slice.loc, off)
 	b.AddAddressOf(ptr, obj)
+	if idx.Equal(i, idx.Zero()) != xtruth.False {
+		b.AddAddressOf(slice.loc, obj)
+	}
 
 	slice.slots = append(slice.slots, Slot{
 		Ptr: ptr,
@@ -163,6 +180,26 @@ func (b *Builder) Chan(gty *types.Chan) *Chan {
 	return b.omap[m].(*Chan)
 }
 
+func (b *Builder) Interface(gty *types.Interface) *Interface {
+	m := b.GoType(gty).Gen()
+	b.walkObj(m)
+	return b.omap[m].(*Interface)
+}
+
+// MakeInterface generates an interface value of type gty whose box
+// holds a copy of v, a value of type cty, and returns it.  The box is
+// generated with class memory.Heap.  If v is memory.NoLoc, the box is
+// left as is.
+func (b *Builder) MakeInterface(gty *types.Interface, cty types.Type, v memory.Loc) *Interface {
+	iface := b.Interface(gty)
+	iface.Concrete = b.Class(memory.Heap).FromGoType(cty)
+	b.AddAddressOf(iface.loc, iface.Concrete)
+	if v != memory.NoLoc {
+		b.AddTransfer(iface.Concrete, v)
+	}
+	return iface
+}
+
 func (b *Builder) Object(m memory.Loc) Object {
 	return b.omap[m]
 }
@@ -187,10 +224,15 @@ func (b *Builder) Pointer(gtype *types.Pointer) *Pointer {
 // Func makes a function object.  It is for top level functions
 // which may or may not be declared.  `declName` must be empty
 // iff the associated function is not declared.
+//
+// The memory location of a function is the root of a region
+// whose children are pointers to the receiver (if any), the
+// parameters, and the results, in that order.  The function
+// location points to itself.
 func (b *Builder) Func(sig *types.Signature, declName string, opaque memory.Attrs) *Func {
 	typ := b.ts.FromGoType(sig)
-	_, obj := b.Type(typ).WithPointer()
-	fn := newFunc(obj, typ)
+	loc := b.Type(typ).Class(memory.Local).Attrs(opaque).Gen()
+	fn := newFunc(loc, typ)
 	fn.declName = declName
 
 	b.mmod.AddAddressOf(fn.loc, fn.loc)
@@ -199,38 +241,80 @@ func (b *Builder) Func(sig *types.Signature, declName string, opaque memory.Attr
 	fn.variadic = sig.Variadic()
 	fn.results = make([]memory.Loc, sig.Results().Len())
 
-	b.Class(memory.Local) // for all params and returns
-
-	recv := sig.Recv()
-
-	if recv != nil {
-		b.mgp.Type(b.ts.FromGoType(recv.Type()))
-		obj, fn.recv = b.mmod.WithPointer(b.mgp)
-		b.walkObj(obj)
+	slot := loc + 1
+	if sig.Recv() != nil {
+		fn.recv = slot
+		b.funcSlot(slot, memory.IsParam|opaque)
+		slot++
 	}
-	params := sig.Params()
-	N := params.Len()
-	for i := 0; i < N; i++ {
-		param := params.At(i)
-		pty := b.ts.FromGoType(param.Type().Underlying())
-		obj, fn.params[i] =
-			b.Pos(param.Pos()).Type(pty).Attrs(memory.IsParam | opaque).WithPointer()
-		b.walkObj(obj)
+	for i := range fn.params {
+		fn.params[i] = slot
+		b.funcSlot(slot, memory.IsParam|opaque)
+		slot++
 	}
-	rets := sig.Results()
-	N = rets.Len()
-	for i := 0; i < N; i++ {
-		ret := rets.At(i)
-		rty := b.ts.FromGoType(ret.Type())
-		obj, fn.results[i] =
-			b.Pos(ret.Pos()).Type(rty).Attrs(memory.IsReturn | opaque).WithPointer()
-		b.walkObj(obj)
+	for i := range fn.results {
+		fn.results[i] = slot
+		b.funcSlot(slot, memory.IsReturn|opaque)
+		slot++
 	}
-	// TBD: FreeVars
+	// panics are not part of the type, like free variables.
+	_, fn.panicv = b.GoType(emptyIface).Class(memory.Local).Attrs(opaque).WithPointer()
+	b.funcSlot(fn.panicv, memory.IsReturn|opaque)
 	b.omap[fn.loc] = fn
 	return fn
 }
 
+var emptyIface = types.NewInterfaceType(nil, nil)
+
+// Panic adds the constraints for fn panicking with the value v, of
+// interface type.
+func (b *Builder) Panic(fn *Func, v memory.Loc) {
+	b.AddStore(fn.panicv, v)
+}
+
+// Recover adds the constraints for dst = recover() in fn.
+func (b *Builder) Recover(fn *Func, dst memory.Loc) {
+	b.AddLoad(dst, fn.panicv)
+}
+
+// Unwind adds the constraints for the panics of callee propagating
+// to caller.
+func (b *Builder) Unwind(caller, callee *Func) {
+	b.AddTransfer(b.mmod.Obj(caller.panicv), b.mmod.Obj(callee.panicv))
+}
+
+// AddFree adds a slot for a free variable of type gty to fn,
+// returning the slot.  Like parameters, the slot is a pointer
+// to an object of type gty.
+func (b *Builder) AddFree(fn *Func, gty types.Type) memory.Loc {
+	as := b.mmod.Attrs(fn.loc) & memory.IsOpaque
+	_, slot := b.GoType(gty).Class(memory.Local).Attrs(as).WithPointer()
+	b.funcSlot(slot, as)
+	fn.free = append(fn.free, slot)
+	return slot
+}
+
+// Bind binds the free variables of fn to the values at the locations
+// free, as when creating a closure of fn.
+func (b *Builder) Bind(fn *Func, free []memory.Loc) {
+	for i, v := range free {
+		b.AddStore(fn.free[i], v)
+	}
+}
+
+// funcSlot marks the function slot ptr and the object to
+// which it points with attributes as, and creates the
+// associated objects.
+func (b *Builder) funcSlot(ptr memory.Loc, as memory.Attrs) {
+	b.mmod.AddAttrs(ptr, as)
+	obj := b.mmod.Obj(ptr)
+	end := obj + memory.Loc(b.mmod.Lsize(obj))
+	for m := obj; m < end; m++ {
+		b.mmod.AddAttrs(m, as)
+	}
+	b.walkObj(obj)
+}
+
 func (b *Builder) FromGoType(gt types.Type) memory.Loc {
 	var res memory.Loc
 	switch ty := gt.Underlying().(type) {
@@ -253,7 +337,7 @@ func (b *Builder) FromGoType(gt types.Type) memory.Loc {
 	case *types.Basic:
 		res = b.Gen()
 	case *types.Interface:
-		res = b.Gen()
+		res = b.Interface(ty).Loc()
 
 	default:
 		fmt.Printf("genValueLoc: default switch ty: %s\n", ty)
@@ -353,6 +437,9 @@ func (b *Builder) walkObj(m memory.Loc) {
 		}
 
 	case typeset.Interface:
+		if b.omap[m] == nil {
+			b.omap[m] = newInterface(m, ty)
+		}
 	case typeset.Func:
 	case typeset.Named:
 		panic("named foo")
@@ -426,3 +513,13 @@ func (b *Builder) PlainDecodeObjects(src io.Reader) error {
 	}
 	return nil
 }
+
+// Close adds the constraints for closing the channel c.
+//
+// Receiving from a closed channel gives the zero value, so closing
+// is modeled as sending nil.
+func (b *Builder) Close(c *Chan) {
+	z := b.Type(b.ts.Elem(c.typ)).Gen()
+	b.AddAddressOf(z, b.mmod.Zero())
+	c.Send(z, b.mmod)
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"github.com/go-air/pal/memory"
+	"github.com/go-air/pal/typeset"
+)
+
+// Append adds the constraints for dst = append(s, xs...) where s
+// and xs are slices of the same type as dst.
+//
+// The result may share the backing store of s, in which case the
+// elements of xs are stored in it, or it may have a new backing
+// store, in which case the elements of s and xs are copied to the
+// slots of dst.  If dst has no slots, one is added for any index.
+func (b *Builder) Append(dst *Slice, s, xs memory.Loc) {
+	b.AddTransfer(dst.loc, s)
+	ps := b.elems(dst.typ, s)
+	pxs := b.elems(dst.typ, xs)
+	b.storeElems(ps, pxs, b.ts.Elem(dst.typ))
+
+	if len(dst.slots) == 0 {
+		b.AddSlot(dst, b.indexing.Var())
+	}
+	for i := range dst.slots {
+		obj := dst.slots[i].Obj
+		b.AddLoad(obj, ps)
+		b.AddLoad(obj, pxs)
+	}
+	dst.Len = b.indexing.Var()
+	dst.Cap = b.indexing.Var()
+	sobj, ok := b.omap[s].(*Slice)
+	if !ok || sobj.Len == nil {
+		return
+	}
+	xobj, ok := b.omap[xs].(*Slice)
+	if !ok || xobj.Len == nil {
+		return
+	}
+	dst.Len = b.indexing.Plus(sobj.Len, xobj.Len)
+}
+
+// Copy adds the constraints for copy(dst, src), where dst and src
+// are slices of type ty: the elements of src are stored in the
+// elements of dst.
+func (b *Builder) Copy(ty typeset.Type, dst, src memory.Loc) {
+	b.storeElems(b.elems(ty, dst), b.elems(ty, src), b.ts.Elem(ty))
+}
+
+// elems returns a pointer to all the elements of the slice s of
+// type ty.
+func (b *Builder) elems(ty typeset.Type, s memory.Loc) memory.Loc {
+	res := b.Type(b.ts.PointerTo(b.ts.Elem(ty))).Gen()
+	b.AddTransferIndex(res, s, b.indexing.Var())
+	return res
+}
+
+// storeElems stores the elements of type elemTy pointed to by src
+// in those pointed to by dst.
+func (b *Builder) storeElems(dst, src memory.Loc, elemTy typeset.Type) {
+	tmp := b.Type(elemTy).Gen()
+	b.walkObj(tmp)
+	b.AddLoad(tmp, src)
+	b.AddStore(dst, tmp)
+}
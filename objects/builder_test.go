@@ -26,3 +26,109 @@ func TestBuilder(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestMakeInterface(t *testing.T) {
+	b := NewBuilder("", indexing.ConstVals())
+	ptrTy := types.NewPointer(types.Typ[types.Int])
+	x, p := b.GoType(types.Typ[types.Int]).Class(memory.Local).WithPointer()
+	iface := b.MakeInterface(types.NewInterfaceType(nil, nil), ptrTy, p)
+	if b.Object(iface.Loc()) != iface {
+		t.Fatalf("no interface object")
+	}
+	mdl := b.Memory()
+	mdl.Solve()
+	if pts := mdl.PointsToFor(nil, iface.Loc()); len(pts) != 1 || pts[0] != iface.Concrete {
+		t.Errorf("pts(iface) = %v, expected [%d]", pts, iface.Concrete)
+	}
+	if pts := mdl.PointsToFor(nil, iface.Concrete); len(pts) != 1 || pts[0] != x {
+		t.Errorf("pts(box) = %v, expected [%d]", pts, x)
+	}
+}
+
+func TestClosure(t *testing.T) {
+	b := NewBuilder("", indexing.ConstVals())
+	ptrTy := types.NewPointer(types.Typ[types.Int])
+	fn := b.Func(types.NewSignature(nil, nil, nil, false), "f$1", memory.NoAttrs)
+	free := b.AddFree(fn, ptrTy)
+	if fn.NumFree() != 1 || fn.FreeLoc(0) != free {
+		t.Fatalf("free slots")
+	}
+	x, p := b.GoType(types.Typ[types.Int]).Class(memory.Local).WithPointer()
+	b.Bind(fn, []memory.Loc{p})
+	mdl := b.Memory()
+	mdl.Solve()
+	if pts := mdl.PointsToFor(nil, mdl.Obj(free)); len(pts) != 1 || pts[0] != x {
+		t.Errorf("pts(free) = %v, expected [%d]", pts, x)
+	}
+}
+
+func TestAppendCopy(t *testing.T) {
+	b := NewBuilder("", indexing.ConstVals())
+	sTy := types.NewSlice(types.NewPointer(types.Typ[types.Int]))
+	ints := func() (memory.Loc, *Slice) {
+		x, p := b.GoType(types.Typ[types.Int]).Class(memory.Local).WithPointer()
+		s := b.Slice(sTy, nil, nil)
+		b.AddStore(s.Loc(), p)
+		return x, s
+	}
+	x, xs := ints()
+	y, ys := ints()
+	z, zs := ints()
+	dst := b.Slice(sTy, nil, nil)
+	b.Append(dst, xs.Loc(), ys.Loc())
+	b.Copy(xs.Type(), zs.Loc(), dst.Loc())
+	mdl := b.Memory()
+	mdl.Solve()
+	elems := func(s *Slice) map[memory.Loc]bool {
+		res := map[memory.Loc]bool{}
+		for _, e := range mdl.PointsToFor(nil, s.Loc()) {
+			for _, v := range mdl.PointsToFor(nil, e) {
+				res[v] = true
+			}
+		}
+		return res
+	}
+	if e := elems(dst); !e[x] || !e[y] || e[z] {
+		t.Errorf("append: %v", e)
+	}
+	if e := elems(xs); !e[x] || !e[y] || e[z] {
+		t.Errorf("append in place: %v", e)
+	}
+	if e := elems(zs); !e[x] || !e[y] || !e[z] {
+		t.Errorf("copy: %v", e)
+	}
+}
+
+func TestClose(t *testing.T) {
+	b := NewBuilder("", indexing.ConstVals())
+	c := b.Chan(types.NewChan(types.SendRecv, types.NewPointer(types.Typ[types.Int])))
+	r := b.GoType(types.NewPointer(types.Typ[types.Int])).Gen()
+	b.Close(c)
+	c.Recv(r, b.Memory())
+	mdl := b.Memory()
+	mdl.Solve()
+	if pts := mdl.PointsToFor(nil, r); len(pts) != 1 || pts[0] != mdl.Zero() {
+		t.Errorf("recv after close: %v", pts)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	b := NewBuilder("", indexing.ConstVals())
+	sig := types.NewSignature(nil, nil, nil, false)
+	caller := b.Func(sig, "caller", memory.NoAttrs)
+	callee := b.Func(sig, "callee", memory.NoAttrs)
+	deferred := b.Func(sig, "deferred", memory.NoAttrs)
+	v := b.Interface(emptyIface).Loc()
+	box := b.GoType(types.Typ[types.Int]).Class(memory.Heap).Gen()
+	b.AddAddressOf(v, box)
+	r := b.Interface(emptyIface).Loc()
+	b.Panic(callee, v)
+	b.Unwind(caller, callee)
+	b.Unwind(deferred, caller)
+	b.Recover(deferred, r)
+	mdl := b.Memory()
+	mdl.Solve()
+	if pts := mdl.PointsToFor(nil, r); len(pts) != 1 || pts[0] != box {
+		t.Errorf("recovered %v, expected [%d]", pts, box)
+	}
+}
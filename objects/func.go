@@ -30,6 +30,7 @@ type Func struct {
 	recv     memory.Loc
 	params   []memory.Loc
 	results  []memory.Loc
+	panicv   memory.Loc
 	variadic bool
 }
 
@@ -53,6 +54,14 @@ func (f *Func) RecvLoc(i int) memory.Loc {
 	return f.recv
 }
 
+func (f *Func) FreeLoc(i int) memory.Loc {
+	return f.free[i]
+}
+
+func (f *Func) NumFree() int {
+	return len(f.free)
+}
+
 func (f *Func) ParamLoc(i int) memory.Loc {
 	return f.params[i]
 }
@@ -69,6 +78,13 @@ func (f *Func) NumResults() int {
 	return len(f.results)
 }
 
+// PanicLoc returns the slot of the values with which f may panic,
+// including those of the functions it calls.  Like parameters, it
+// is a pointer to an object, of type interface{}.
+func (f *Func) PanicLoc() memory.Loc {
+	return f.panicv
+}
+
 func (f *Func) PlainEncode(w io.Writer) error {
 	var err error
 	h := hdr{&f.object}
@@ -156,7 +172,11 @@ func (f *Func) PlainEncode(w io.Writer) error {
 			return err
 		}
 	}
-	return nil
+	err = plain.Put(w, " ")
+	if err != nil {
+		return err
+	}
+	return f.panicv.PlainEncode(w)
 }
 
 func (f *Func) plainDecode(r io.Reader) error {
@@ -238,5 +258,9 @@ func (f *Func) plainDecode(r io.Reader) error {
 			return fmt.Errorf("func decode result %d: %w", i, err)
 		}
 	}
-	return nil
+	err = plain.Expect(r, " ")
+	if err != nil {
+		return err
+	}
+	return f.panicv.PlainDecode(r)
 }
@@ -34,6 +34,7 @@ func fclob(o Object) {
 	f.variadic = false
 	f.params = nil
 	f.results = nil
+	f.panicv = 0
 }
 
 func TestFuncPlain(t *testing.T) {
@@ -17,18 +17,36 @@ package objects
 import (
 	"io"
 
+	"github.com/go-air/pal/internal/plain"
 	"github.com/go-air/pal/memory"
+	"github.com/go-air/pal/typeset"
 )
 
+// Interface object
+//
+// An interface value i.loc points to boxes.  A box is a memory
+// location holding the concrete value of the interface, and its
+// type is the dynamic type of the interface.
+//
+// Concrete is the box of an interface made by Builder.MakeInterface,
+// and memory.NoLoc for other interface values.
 type Interface struct {
 	object
 	Concrete memory.Loc
 }
 
+func newInterface(loc memory.Loc, ty typeset.Type) *Interface {
+	return &Interface{object: object{kind: kinterface, loc: loc, typ: ty}}
+}
+
 func (i *Interface) PlainEncode(w io.Writer) error {
-	return nil
+	return plain.EncodeJoin(w, " ", hdr{&i.object}, i.Concrete)
 }
 
 func (i *Interface) plainDecode(r io.Reader) error {
-	return nil
+	err := plain.Expect(r, " ")
+	if err != nil {
+		return err
+	}
+	return (&i.Concrete).PlainDecode(r)
 }
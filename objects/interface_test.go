@@ -0,0 +1,32 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"testing"
+)
+
+func TestInterface(t *testing.T) {
+	i := newInterface(11, 16)
+	i.Concrete = 27
+	if err := testRoundTrip(i, func(o Object) {
+		i := o.(*Interface)
+		i.loc = 0
+		i.typ = 0
+		i.Concrete = 0
+	}, false); err != nil {
+		t.Error(err)
+	}
+}
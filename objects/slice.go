@@ -30,14 +30,16 @@ import (
 //
 // Each slice has 0 or more slots.  A slot is a triple (p, m, i) such that
 //   1. p = &m
-//   2. p = ptr(s) + i
+//   2. p = ptr(s) + i*sz, where sz is the logical size of the elements.
+//   3. ptr(s) = &m if i may be 0.
+//
+// The slot objects m are memory.Rel locations relative to a root
+// representing the backing store of the slice, at offset 1 + i*sz.
 //
 // A lookup or update s[j] will have indexing.I type for j.  The semantics
 // are that if the indexing model `idx` is such that idx.Equal(i, j) is not
-// xtruth.False, for some slot (p, m, i), then &s[j] may point to m.
-//
-// For the moment, we set ptr(s) == &nil to guarantee that nil derefs are
-// considered possible.  When indexing gets richer, perhaps we can do more.
+// xtruth.False, for some slot (p, m, i), then &s[j] may point to m.  This
+// results from the solver's treatment of ptr(s) + j*sz.
 type Slice struct {
 	object
 	Len   indexing.I
@@ -0,0 +1,153 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objects
+
+import (
+	"go/token"
+	"go/types"
+	"math/rand"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/memory"
+)
+
+// randBuild builds a pseudo random model from seed with objects of various
+// types whose pointers, all of type *int, are related by random
+// constraints and calls.
+func randBuild(seed int64, n int) *Builder {
+	rnd := rand.New(rand.NewSource(seed))
+	b := NewBuilder("", indexing.ConstVals())
+	mm := b.Memory()
+	intTy := types.Typ[types.Int]
+	pTy := types.NewPointer(intTy)
+	ppTy := types.NewPointer(pTy)
+	sTy := types.NewStruct([]*types.Var{
+		types.NewVar(token.NoPos, nil, "a", pTy),
+		types.NewVar(token.NoPos, nil, "b", pTy)}, nil)
+	tup := func(ts ...types.Type) *types.Tuple {
+		vs := make([]*types.Var, len(ts))
+		for i, t := range ts {
+			vs[i] = types.NewVar(token.NoPos, nil, "", t)
+		}
+		return types.NewTuple(vs...)
+	}
+	sig := types.NewSignature(nil, tup(pTy, pTy), tup(pTy), false)
+
+	var ints, ptrs, pptrs []memory.Loc
+	var maps []*Map
+	var chans []*Chan
+	var fns []*Func
+	for i := 0; i < n; i++ {
+		switch rnd.Intn(8) {
+		case 0:
+			ints = append(ints, b.GoType(intTy).Gen())
+		case 1:
+			ptrs = append(ptrs, b.Pointer(pTy).Loc())
+		case 2:
+			pptrs = append(pptrs, b.Pointer(ppTy).Loc())
+		case 3:
+			s := b.Struct(sTy)
+			ptrs = append(ptrs, s.Field(0), s.Field(1))
+		case 4:
+			a := b.Array(types.NewArray(pTy, 3))
+			ptrs = append(ptrs, a.At(0), a.At(1), a.At(2))
+		case 5:
+			m := b.Map(types.NewMap(pTy, pTy))
+			maps = append(maps, m)
+		case 6:
+			c := b.Chan(types.NewChan(types.SendRecv, pTy))
+			chans = append(chans, c)
+		case 7:
+			fns = append(fns, b.Func(sig, "", memory.NoAttrs))
+		}
+	}
+	if len(ints) == 0 || len(ptrs) == 0 {
+		return b
+	}
+	ptr := func() memory.Loc { return ptrs[rnd.Intn(len(ptrs))] }
+	for i := 0; i < 2*n; i++ {
+		switch rnd.Intn(7) {
+		case 0:
+			b.AddAddressOf(ptr(), ints[rnd.Intn(len(ints))])
+		case 1:
+			b.AddTransfer(ptr(), ptr())
+		case 2:
+			if len(pptrs) > 0 {
+				pp := pptrs[rnd.Intn(len(pptrs))]
+				b.AddAddressOf(pp, ptr())
+				b.AddStore(pp, ptr())
+				b.AddLoad(ptr(), pp)
+			}
+		case 3:
+			if len(maps) > 0 {
+				m := maps[rnd.Intn(len(maps))]
+				m.Update(ptr(), ptr(), mm)
+				m.Lookup(ptr(), mm)
+			}
+		case 4:
+			if len(chans) > 0 {
+				c := chans[rnd.Intn(len(chans))]
+				c.Send(ptr(), mm)
+				c.Recv(ptr(), mm)
+			}
+		case 5:
+			if len(fns) > 0 {
+				f := fns[rnd.Intn(len(fns))]
+				b.Call(f, ptr(), []memory.Loc{ptr(), ptr()})
+			}
+		case 6:
+			if len(fns) > 0 {
+				f := fns[rnd.Intn(len(fns))]
+				b.AddLoad(ptr(), f.ParamLoc(rnd.Intn(2)))
+				b.AddStore(f.ResultLoc(0), ptr())
+			}
+		}
+	}
+	return b
+}
+
+func TestSolveParallel(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		seq := randBuild(seed, 50).Memory()
+		seq.SetSolveOpts(memory.SolveOpts{})
+		seq.Solve()
+		for _, p := range []int{2, 3, 8} {
+			par := randBuild(seed, 50).Memory()
+			opts := memory.DefaultSolveOpts()
+			opts.Parallel = p
+			par.SetSolveOpts(opts)
+			par.Solve()
+			if par.Len() != seq.Len() {
+				t.Fatalf("seed %d: len %d != %d", seed, par.Len(), seq.Len())
+			}
+			for i := 0; i < seq.Len(); i++ {
+				m := seq.At(i)
+				exp := seq.PointsToFor(nil, m)
+				got := par.PointsToFor(nil, m)
+				if len(exp) != len(got) {
+					t.Errorf("seed %d par %d: pts(%d) = %v, expected %v", seed, p, m, got, exp)
+					continue
+				}
+				for j := range exp {
+					if exp[j] != got[j] {
+						t.Errorf("seed %d par %d: pts(%d) = %v, expected %v", seed, p, m, got, exp)
+						break
+					}
+				}
+			}
+		}
+	}
+}
@@ -18,10 +18,12 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/go-air/pal/indexing"
 	"github.com/go-air/pal/internal/plain"
 	"github.com/go-air/pal/memory"
+	"github.com/go-air/pal/typeset"
 )
 
 // PkgRes represents results for a package.
@@ -30,6 +32,21 @@ type PkgRes struct {
 	indexing indexing.T
 	Start    memory.Loc
 	MemModel *memory.Model // provides memory.Loc operations
+
+	// TypeSet holds the types of the locations in MemModel.
+	TypeSet *typeset.TypeSet
+
+	// Symbols maps the names of exported package members and
+	// methods to their locations in MemModel.  Methods are named
+	// with their receiver type, such as (*T).Get.  For a global
+	// variable, the location is a pointer to the variable.  For a
+	// function or method, it is the location of the function.
+	Symbols map[string]memory.Loc
+
+	// Spawns are the locations of the goroutine spawn sites
+	// in MemModel.  Each spawn site points to the values passed
+	// to the goroutines it starts.
+	Spawns []memory.Loc
 }
 
 func NewPkgRes(pkgPath string, vs indexing.T) *PkgRes {
@@ -38,7 +55,40 @@ func NewPkgRes(pkgPath string, vs indexing.T) *PkgRes {
 		PkgPath:  pkgPath,
 		indexing: vs,
 		Start:    memory.Loc(1),
-		MemModel: mdl}
+		MemModel: mdl,
+		TypeSet:  typeset.New(),
+		Symbols:  make(map[string]memory.Loc)}
+}
+
+// Lookup returns the location of the exported package
+// member or method 'name', named as in Symbols, or memory.NoLoc
+// if there is none.
+func (pkg *PkgRes) Lookup(name string) memory.Loc {
+	return pkg.Symbols[name]
+}
+
+// Shared returns the heap locations which are reachable from more
+// than one goroutine spawn site, in increasing order.
+func (pkg *PkgRes) Shared() []memory.Loc {
+	mdl := pkg.MemModel
+	count := make(map[memory.Loc]int)
+	var reach []memory.Loc
+	for _, g := range pkg.Spawns {
+		reach = mdl.Reachable(reach[:0], g)
+		for _, m := range reach {
+			if mdl.Class(m) == memory.Heap {
+				count[m]++
+			}
+		}
+	}
+	var res []memory.Loc
+	for m, n := range count {
+		if n > 1 {
+			res = append(res, m)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
 }
 
 func (pkg *PkgRes) PlainEncode(w io.Writer) error {
@@ -52,7 +102,34 @@ func (pkg *PkgRes) PlainEncode(w io.Writer) error {
 			return nil
 		}
 	}
-	return pkg.MemModel.PlainEncodeConstraints(w)
+	if e := pkg.MemModel.PlainEncodeConstraints(w); e != nil {
+		return e
+	}
+	if e := pkg.TypeSet.PlainEncode(w); e != nil {
+		return e
+	}
+	names := make([]string, 0, len(pkg.Symbols))
+	for name := range pkg.Symbols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if _, e := fmt.Fprintf(w, "%d\n", len(names)); e != nil {
+		return e
+	}
+	for _, name := range names {
+		if _, e := fmt.Fprintf(w, "%s %s\n", name, plain.String(pkg.Symbols[name])); e != nil {
+			return e
+		}
+	}
+	if _, e := fmt.Fprintf(w, "%d\n", len(pkg.Spawns)); e != nil {
+		return e
+	}
+	for _, g := range pkg.Spawns {
+		if _, e := fmt.Fprintf(w, "%s\n", plain.String(g)); e != nil {
+			return e
+		}
+	}
+	return nil
 }
 
 func (pkg *PkgRes) PlainDecode(r io.Reader) error {
@@ -63,13 +140,14 @@ func (pkg *PkgRes) PlainDecode(r io.Reader) error {
 		return fmt.Errorf("1 %w", err)
 	}
 	pkg.PkgPath = pkg.PkgPath[:len(pkg.PkgPath)-1]
-	var start int
+	if err = pkg.Start.PlainDecode(br); err != nil {
+		return fmt.Errorf("2 %w", err)
+	}
 	var n int
-	_, err = fmt.Fscanf(br, "%d:%d\n", &start, &n)
+	_, err = fmt.Fscanf(br, ":%d\n", &n)
 	if err != nil {
 		return fmt.Errorf("2 %w", err)
 	}
-	pkg.Start = memory.Loc(start)
 	pkg.MemModel.Cap(n)
 	spaceBuf := make([]byte, 1)
 	for i := 0; i < n; i++ {
@@ -82,5 +160,46 @@ func (pkg *PkgRes) PlainDecode(r io.Reader) error {
 			return fmt.Errorf("6 %d-'%s'-%w", i, string(spaceBuf), err)
 		}
 	}
-	return pkg.MemModel.PlainDecodeConstraints(br)
+	if err = pkg.MemModel.PlainDecodeConstraints(br); err != nil {
+		return err
+	}
+	if pkg.TypeSet == nil {
+		pkg.TypeSet = typeset.New()
+	}
+	if err = pkg.TypeSet.PlainDecode(br); err != nil {
+		return err
+	}
+	_, err = fmt.Fscanf(br, "%d\n", &n)
+	if err != nil {
+		return fmt.Errorf("7 %w", err)
+	}
+	pkg.Symbols = make(map[string]memory.Loc, n)
+	for i := 0; i < n; i++ {
+		name, err := br.ReadString(' ')
+		if err != nil {
+			return fmt.Errorf("8 %d-%w", i, err)
+		}
+		var m memory.Loc
+		if err = m.PlainDecode(br); err != nil {
+			return fmt.Errorf("9 %d-%w", i, err)
+		}
+		if err = plain.Expect(br, "\n"); err != nil {
+			return fmt.Errorf("10 %d-%w", i, err)
+		}
+		pkg.Symbols[name[:len(name)-1]] = m
+	}
+	_, err = fmt.Fscanf(br, "%d\n", &n)
+	if err != nil {
+		return fmt.Errorf("11 %w", err)
+	}
+	pkg.Spawns = make([]memory.Loc, n)
+	for i := range pkg.Spawns {
+		if err = pkg.Spawns[i].PlainDecode(br); err != nil {
+			return fmt.Errorf("12 %d-%w", i, err)
+		}
+		if err = plain.Expect(br, "\n"); err != nil {
+			return fmt.Errorf("13 %d-%w", i, err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,91 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package results
+
+import (
+	"bytes"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/memory"
+	"github.com/go-air/pal/typeset"
+)
+
+const testPkgPath = "example.com/a"
+
+// symPkgRes gives the results for a package whose memory model
+// has indices which are fresh symbolic variables.
+func symPkgRes() *PkgRes {
+	pkg := NewPkgRes(testPkgPath, indexing.SymbolicIn(testPkgPath))
+	mdl := pkg.MemModel
+	idx := pkg.indexing
+	gp := memory.NewGenParams(pkg.TypeSet).Class(memory.Heap)
+	base := mdl.Gen(gp.GoType(types.NewSlice(types.Typ[types.Int])))
+	gp.Class(memory.Global)
+	e := mdl.GenRel(gp.GoType(types.Typ[types.Int]), base, idx.Var())
+	p := mdl.Gen(gp.GoType(types.NewPointer(types.Typ[types.Int])))
+	q := mdl.Gen(gp.GoType(types.NewPointer(types.Typ[types.Int])))
+	mdl.AddAddressOf(p, e)
+	mdl.AddTransferIndex(q, p, idx.Var())
+	pkg.Symbols["P"] = p
+	pkg.Symbols["Q"] = q
+	pkg.Spawns = []memory.Loc{p, q}
+	return pkg
+}
+
+func TestPkgResRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := symPkgRes().PlainEncode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	enc := buf.String()
+	if !strings.Contains(enc, "$"+testPkgPath+".") {
+		t.Errorf("fresh variables not scoped by %s:\n%s", testPkgPath, enc)
+	}
+
+	var again bytes.Buffer
+	if err := symPkgRes().PlainEncode(&again); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != enc {
+		t.Errorf("encoding not deterministic:\n%s\n%s", enc, again.String())
+	}
+
+	dec := NewPkgRes("", indexing.Symbolic())
+	if err := dec.PlainDecode(strings.NewReader(enc)); err != nil {
+		t.Fatal(err)
+	}
+	if dec.PkgPath != testPkgPath {
+		t.Errorf("pkg path: got %q want %q", dec.PkgPath, testPkgPath)
+	}
+	if dec.Lookup("Q") == memory.NoLoc {
+		t.Errorf("symbols %v", dec.Symbols)
+	}
+	if ty := dec.MemModel.Type(dec.Lookup("P")); dec.TypeSet.Kind(ty) != typeset.Pointer {
+		t.Errorf("type of P: %s", dec.TypeSet.String(ty))
+	}
+	if len(dec.Spawns) != 2 || dec.Spawns[0] != dec.Lookup("P") || dec.Spawns[1] != dec.Lookup("Q") {
+		t.Errorf("spawns %v symbols %v", dec.Spawns, dec.Symbols)
+	}
+	buf.Reset()
+	if err := dec.PlainEncode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != enc {
+		t.Errorf("round trip:\n%s\n%s", enc, buf.String())
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file provides support for calls to builtin functions.
+
+package ssa2pal
+
+import (
+	"go/types"
+
+	"github.com/go-air/pal/memory"
+	"github.com/go-air/pal/objects"
+	"golang.org/x/tools/go/ssa"
+)
+
+// builtin adds the constraints for a call to the builtin b at instr
+// with arguments at locs args and result dst.
+//
+// new is not a call in ssa, but an Alloc. len and cap are done in
+// indexValue.  The remaining builtins which do not move pointers,
+// such as delete, clear, min and max, are no-ops.
+func (p *T) builtin(b *ssa.Builtin, instr ssa.CallInstruction, dst memory.Loc, args []memory.Loc) {
+	c := instr.Common()
+	switch b.Name() {
+	case "append":
+		if dst == memory.NoLoc {
+			return
+		}
+		xs := args[1]
+		if xs == memory.NoLoc || isString(c.Args[1].Type()) {
+			// nil or append([]byte, string...)
+			xs = p.buildr.Memory().Zero()
+		}
+		p.buildr.Pos(c.Pos()).Class(memory.Local).Attrs(memory.NoAttrs)
+		p.buildr.Append(p.buildr.Object(dst).(*objects.Slice), args[0], xs)
+	case "copy":
+		if isString(c.Args[1].Type()) {
+			// copy([]byte, string)
+			return
+		}
+		p.buildr.Pos(c.Pos()).Class(memory.Local).Attrs(memory.NoAttrs)
+		ty := p.buildr.TypeSet().FromGoType(c.Args[0].Type())
+		p.buildr.Copy(ty, args[0], args[1])
+	case "close":
+		p.buildr.Close(p.buildr.Object(args[0]).(*objects.Chan))
+	case "panic":
+		// deferred or go, otherwise it is an ssa.Panic.
+		p.buildr.Panic(p.funcs[instr.Parent()], args[0])
+	case "recover":
+		if dst != memory.NoLoc {
+			p.buildr.Recover(p.funcs[instr.Parent()], dst)
+		}
+	case "ssa:wrapnilchk":
+		p.buildr.AddTransfer(dst, args[0])
+	}
+}
+
+func isString(ty types.Type) bool {
+	b, ok := ty.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsString != 0
+}
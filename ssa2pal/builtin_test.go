@@ -0,0 +1,56 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import "testing"
+
+func TestAppendCopy(t *testing.T) {
+	src := `package p
+
+var X, Y, Z int
+var Append, Spread, Nil, Copy *int
+
+func init() {
+	s := []*int{&X}
+	s = append(s, &Y)
+	Append = s[1]
+	u := append([]*int(nil), s...)
+	Spread = u[0]
+	var n []*int
+	n = append(n, &Z)
+	Nil = n[0]
+	d := make([]*int, 2)
+	copy(d, []*int{&Z})
+	Copy = d[1]
+}
+`
+	for name, vs := range domains {
+		pr := build(t, src, vs())
+		for v, exps := range map[string][]string{
+			"Append": {"Y"},
+			"Spread": {"X", "Y"},
+			"Nil":    {"Z"},
+			"Copy":   {"Z"}} {
+			for _, exp := range exps {
+				if !mayPoint(t, pr, v, exp) {
+					t.Errorf("%s: %s -> %v", name, v, pointees(t, pr, v))
+				}
+			}
+		}
+		if mayPoint(t, pr, "Copy", "X") || mayPoint(t, pr, "Copy", "Y") {
+			t.Errorf("%s: Copy -> %v", name, pointees(t, pr, "Copy"))
+		}
+	}
+}
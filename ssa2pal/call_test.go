@@ -0,0 +1,116 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import "testing"
+
+func TestCallValues(t *testing.T) {
+	src := `package p
+
+var X, Y int
+var Param, Field, MapElt, SliceElt, Nil *int
+
+func x() *int { return &X }
+
+func y() *int { return &Y }
+
+func param(f func() *int) *int { return f() }
+
+type fields struct{ f func() *int }
+
+func nilFunc() *int {
+	var f func() *int
+	return f()
+}
+
+func init() {
+	Param = param(x)
+	s := &fields{f: y}
+	Field = s.f()
+	m := map[string]func() *int{"x": x}
+	MapElt = m["x"]()
+	fs := make([]func() *int, 1)
+	fs[0] = y
+	SliceElt = fs[0]()
+	Nil = nilFunc()
+}
+`
+	for name, vs := range domains {
+		pr := build(t, src, vs())
+		for v, exp := range map[string]string{
+			"Param":    "X",
+			"Field":    "Y",
+			"MapElt":   "X",
+			"SliceElt": "Y"} {
+			if !mayPoint(t, pr, v, exp) {
+				t.Errorf("%s: %s -> %v", name, v, pointees(t, pr, v))
+			}
+		}
+		if pts := pointees(t, pr, "Nil"); len(pts) != 0 {
+			t.Errorf("%s: Nil -> %v", name, pts)
+		}
+	}
+}
+
+func TestCallOpaqueFlow(t *testing.T) {
+	src := `package p
+
+var X int
+var Phi, Field, Closure *int
+
+func x() *int { return &X }
+
+type holder struct{ f func() *int }
+
+func ViaPhi(f func() *int, b bool) {
+	g := x
+	if b {
+		g = f
+	}
+	Phi = g()
+}
+
+func ViaField(f func() *int) {
+	h := &holder{f: f}
+	Field = h.f()
+}
+
+func ViaClosure(f func() *int) {
+	g := func() *int { return f() }
+	Closure = g()
+}
+`
+	for name, vs := range domains {
+		p := buildT(t, src, vs())
+		// f is unknown, so are the calls through values which
+		// may come from it.
+		n := 0
+		for _, site := range p.fcalls {
+			switch site.instr.Parent().Name() {
+			case "ViaPhi", "ViaField", "ViaClosure$1":
+				n++
+				if !site.opaque {
+					t.Errorf("%s: call %s in %s not opaque", name, site.instr, site.instr.Parent())
+				}
+			}
+		}
+		if n != 3 {
+			t.Errorf("%s: %d calls through values", name, n)
+		}
+		if !mayPoint(t, p.pkgres, "Phi", "X") {
+			t.Errorf("%s: Phi -> %v", name, pointees(t, p.pkgres, "Phi"))
+		}
+	}
+}
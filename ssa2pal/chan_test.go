@@ -0,0 +1,90 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import "testing"
+
+func TestSelect(t *testing.T) {
+	src := `package p
+
+var X, Y, Z int
+var P, Q *int
+
+func init() {
+	c0 := make(chan *int, 1)
+	c1 := make(chan *int, 1)
+	c2 := make(chan *int, 1)
+	c1 <- &X
+	c2 <- &Y
+	select {
+	case c0 <- &Z:
+	case p := <-c1:
+		P = p
+	case q, ok := <-c2:
+		if ok {
+			Q = q
+		}
+	}
+}
+`
+	for name, vs := range domains {
+		pr := build(t, src, vs())
+		// the values received are at index 2 and 3 of the tuple
+		// of the select, after the index and recvOk.
+		if !mayPoint(t, pr, "P", "X") || mayPoint(t, pr, "P", "Y") || mayPoint(t, pr, "P", "Z") {
+			t.Errorf("%s: P -> %v", name, pointees(t, pr, "P"))
+		}
+		if !mayPoint(t, pr, "Q", "Y") || mayPoint(t, pr, "Q", "X") || mayPoint(t, pr, "Q", "Z") {
+			t.Errorf("%s: Q -> %v", name, pointees(t, pr, "Q"))
+		}
+	}
+}
+
+func TestClose(t *testing.T) {
+	src := `package p
+
+var X int
+var Open, Closed *int
+
+func init() {
+	c := make(chan *int, 1)
+	c <- &X
+	Open = <-c
+	d := make(chan *int, 1)
+	d <- &X
+	close(d)
+	Closed = <-d
+}
+`
+	for name, vs := range domains {
+		pr := build(t, src, vs())
+		zero := pr.MemModel.Zero()
+		has := func(v string) bool {
+			for _, m := range pointees(t, pr, v) {
+				if m == zero {
+					return true
+				}
+			}
+			return false
+		}
+		// receiving from a closed channel gives nil.
+		if !mayPoint(t, pr, "Closed", "X") || !has("Closed") {
+			t.Errorf("%s: Closed -> %v", name, pointees(t, pr, "Closed"))
+		}
+		if !mayPoint(t, pr, "Open", "X") || has("Open") {
+			t.Errorf("%s: Open -> %v", name, pointees(t, pr, "Open"))
+		}
+	}
+}
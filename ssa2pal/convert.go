@@ -0,0 +1,76 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file provides support for conversions which change the
+// representation of values.
+
+package ssa2pal
+
+import (
+	"go/types"
+
+	"github.com/go-air/pal/memory"
+	"golang.org/x/tools/go/ssa"
+)
+
+// convert generates the loc of the conversion v and the associated
+// constraints.
+//
+// The conversions are
+//  1. between numeric types and strings, which have no pointers.
+//  2. between strings and []byte or []rune, which copy to a
+//     new backing store without pointers.
+//  3. between unsafe.Pointer and pointers, which preserve the
+//     pointer.
+//  4. from slices to array pointers, which point to the array
+//     whose first element is that of the slice.
+func (p *T) convert(v *ssa.Convert) memory.Loc {
+	if isUnsafePointer(v.Type()) || isUnsafePointer(v.X.Type()) {
+		if res, ok := p.unsafeConvert(v); ok {
+			return res
+		}
+	}
+	x := p.vmap[v.X]
+	switch dty := v.Type().Underlying().(type) {
+	case *types.Slice:
+		// from a string
+		p.buildr.Class(memory.Heap)
+		return p.buildr.FromGoType(dty)
+	case *types.Pointer:
+		res := p.buildr.FromGoType(dty)
+		switch v.X.Type().Underlying().(type) {
+		case *types.Slice:
+			// the array starts 1 before its first element.
+			p.buildr.AddTransferIndex(res, x, p.indexing.FromInt64(-1))
+		default:
+			p.buildr.AddTransfer(res, x)
+		}
+		return res
+	}
+	res := p.buildr.FromGoType(v.Type())
+	if isUnsafePointer(v.Type()) && isPointer(v.X.Type()) {
+		p.buildr.AddTransfer(res, x)
+	}
+	return res
+}
+
+func isUnsafePointer(ty types.Type) bool {
+	b, ok := ty.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.UnsafePointer
+}
+
+func isPointer(ty types.Type) bool {
+	_, ok := ty.Underlying().(*types.Pointer)
+	return ok
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import "testing"
+
+func TestConvert(t *testing.T) {
+	src := `package p
+
+import "unsafe"
+
+var X, Y int
+var Unsafe, Named, Field, Iface, Elt0, Elt1, Byte *int
+
+type IP *int
+
+type S struct{ p *int }
+
+type S2 S
+
+type M interface{ M() }
+
+func (S) M() {}
+
+func init() {
+	// Convert
+	Unsafe = (*int)(unsafe.Pointer(&X))
+	// ChangeType
+	var ip IP = IP(&X)
+	Named = ip
+	Field = S2(S{p: &Y}).p
+	// ChangeInterface
+	var m M = S{p: &X}
+	var e interface{} = m
+	Iface = e.(S).p
+	// slice to array pointer
+	a := (*[2]*int)([]*int{&X, &Y})
+	Elt0 = a[0]
+	Elt1 = a[1]
+	// string and []byte
+	b := []byte(string([]byte("pal")))
+	Byte = (*int)(unsafe.Pointer(&b[0]))
+}
+`
+	for name, vs := range domains {
+		pr := build(t, src, vs())
+		for v, exp := range map[string]string{
+			"Unsafe": "X",
+			"Named":  "X",
+			"Field":  "Y",
+			"Iface":  "X",
+			"Elt0":   "X",
+			"Elt1":   "Y"} {
+			if !mayPoint(t, pr, v, exp) {
+				t.Errorf("%s: %s -> %v", name, v, pointees(t, pr, v))
+			}
+		}
+		if name == "consts" && (mayPoint(t, pr, "Elt0", "Y") || mayPoint(t, pr, "Elt1", "X")) {
+			t.Errorf("%s: Elt0 -> %v, Elt1 -> %v", name, pointees(t, pr, "Elt0"), pointees(t, pr, "Elt1"))
+		}
+		// the bytes are a new backing store, which has no pointers.
+		bs := pointees(t, pr, "Byte")
+		if len(bs) == 0 {
+			t.Errorf("%s: Byte -> %v", name, bs)
+		}
+		for _, v := range []string{"X", "Y"} {
+			if mayPoint(t, pr, "Byte", v) {
+				t.Errorf("%s: Byte -> %s", name, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file provides support for calls whose targets depend on
+// the points-to solution, such as interface method invocations and
+// calls through function values.
+
+package ssa2pal
+
+import (
+	"go/token"
+	"go/types"
+
+	"github.com/go-air/pal/memory"
+	"github.com/go-air/pal/objects"
+	"github.com/go-air/pal/typeset"
+	"golang.org/x/tools/go/ssa"
+)
+
+// invokeSite represents an invoke mode call.
+type invokeSite struct {
+	instr  ssa.CallInstruction
+	call   *ssa.CallCommon
+	iface  memory.Loc
+	dst    memory.Loc
+	args   []memory.Loc
+	done   map[callKey]bool
+	opaque bool
+}
+
+// callKey identifies a call to a method with a receiver.
+type callKey struct {
+	fn   *ssa.Function
+	recv memory.Loc
+}
+
+// funcSite represents a call through a function value.
+type funcSite struct {
+	instr  ssa.CallInstruction
+	fval   memory.Loc
+	dst    memory.Loc
+	args   []memory.Loc
+	done   map[memory.Loc]bool
+	opaque bool
+}
+
+func (p *T) callValue(instr ssa.CallInstruction, fval, dst memory.Loc, args []memory.Loc) {
+	p.fcalls = append(p.fcalls, &funcSite{
+		instr: instr,
+		fval:  fval,
+		dst:   dst,
+		args:  args,
+		done:  make(map[memory.Loc]bool)})
+}
+
+func (p *T) invoke(instr ssa.CallInstruction, dst memory.Loc) {
+	c := instr.Common()
+	site := &invokeSite{
+		instr: instr,
+		call:  c,
+		iface: p.vmap[c.Value],
+		dst:   dst,
+		args:  make([]memory.Loc, len(c.Args)+1),
+		done:  make(map[callKey]bool)}
+	for i, arg := range c.Args {
+		site.args[i+1] = p.vmap[arg]
+	}
+	p.invokes = append(p.invokes, site)
+}
+
+// solve solves the memory model, resolving the targets of dynamic
+// calls and type assertions and adding the resulting constraints
+// until there are no more.
+func (p *T) solve() {
+	mdl := p.buildr.Memory()
+	p.addOpaquePointees()
+	mdl.Solve()
+	for p.resolveDynamic() {
+		p.addOpaquePointees()
+		mdl.SolveIncremental()
+	}
+	p.markUnsafePointees()
+}
+
+// resolveDynamic adds calls to the targets of dynamic calls, the
+// results of type assertions and the results of unsafe pointer
+// arithmetic found in the current solution, returning whether there
+// were any new ones.
+func (p *T) resolveDynamic() bool {
+	res := false
+	// p.invokes, p.fcalls and p.asserts may grow as targets are
+	// translated.
+	for i := 0; i < len(p.invokes); i++ {
+		if p.resolveInvoke(p.invokes[i]) {
+			res = true
+		}
+		p.genPending()
+	}
+	for i := 0; i < len(p.fcalls); i++ {
+		if p.resolveFuncCall(p.fcalls[i]) {
+			res = true
+		}
+		p.genPending()
+	}
+	for i := 0; i < len(p.asserts); i++ {
+		if p.resolveAssert(p.asserts[i]) {
+			res = true
+		}
+	}
+	for _, site := range p.unsafeSites {
+		if p.resolveUnsafe(site) {
+			res = true
+		}
+	}
+	return res
+}
+
+// resolveInvoke adds the calls to the methods of the dynamic types
+// in the points-to set of the interface of site which have not yet
+// been called.  If the interface may hold values of unknown type,
+// then the methods of the package's types which implement the
+// interface are called with opaque receivers, and the result is
+// opaque.
+//
+// Only the types declared in the scope of the current package are
+// considered then, so the methods of imported types and of types
+// declared in functions are not called, and the effects of such
+// methods on the arguments of site are not modelled.
+func (p *T) resolveInvoke(site *invokeSite) bool {
+	mdl := p.buildr.Memory()
+	res := false
+	unknown := mdl.Attrs(site.iface).IsOpaque()
+	for _, box := range mdl.PointsToFor(nil, site.iface) {
+		if box == mdl.Zero() {
+			continue
+		}
+		ty, ok := p.boxTypes[box]
+		if !ok || mdl.Attrs(box).IsOpaque() {
+			unknown = true
+			continue
+		}
+		if p.callMethod(site, ty, box) {
+			res = true
+		}
+	}
+	if !unknown || site.opaque {
+		return res
+	}
+	site.opaque = true
+	p.markOpaque(site.dst)
+	iface := site.call.Value.Type().Underlying().(*types.Interface)
+	scope := p.pkg.Pkg.Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || types.IsInterface(tn.Type()) {
+			continue
+		}
+		for _, ty := range [...]types.Type{tn.Type(), types.NewPointer(tn.Type())} {
+			if !types.Implements(ty, iface) {
+				continue
+			}
+			recv := p.buildr.Pos(site.call.Pos()).Class(memory.Heap).Attrs(memory.IsOpaque).FromGoType(ty)
+			p.markOpaque(recv)
+			p.callMethod(site, ty, recv)
+			res = true
+			break
+		}
+	}
+	return res
+}
+
+// resolveFuncCall adds the calls to the functions in the points-to
+// set of the function value of site which have not yet been called.
+// If the function value may be unknown, the result is opaque.
+func (p *T) resolveFuncCall(site *funcSite) bool {
+	mdl := p.buildr.Memory()
+	res := false
+	unknown := mdl.Attrs(site.fval).IsOpaque()
+	for _, floc := range mdl.PointsToFor(nil, site.fval) {
+		if floc == mdl.Zero() || site.done[floc] {
+			continue
+		}
+		fn, ok := p.buildr.Object(floc).(*objects.Func)
+		if !ok {
+			unknown = true
+			continue
+		}
+		site.done[floc] = true
+		p.callFunc(site.instr, fn, site.dst, site.args)
+		res = true
+	}
+	if unknown && !site.opaque {
+		site.opaque = true
+		p.markOpaque(site.dst)
+		res = true
+	}
+	return res
+}
+
+// callMethod calls the method of site for the receiver type ty
+// with receiver value recv, if it has not already been called.
+func (p *T) callMethod(site *invokeSite, ty types.Type, recv memory.Loc) bool {
+	m := site.call.Method
+	if p.pkg.Prog.MethodSets.MethodSet(ty).Lookup(m.Pkg(), m.Name()) == nil {
+		// type not consistent with the interface.
+		return false
+	}
+	fn := p.pkg.Prog.LookupMethod(ty, m.Pkg(), m.Name())
+	key := callKey{fn: fn, recv: recv}
+	if site.done[key] {
+		return false
+	}
+	site.done[key] = true
+	if fn.Blocks == nil {
+		// defined elsewhere: results are unknown.
+		p.markOpaque(site.dst)
+		return true
+	}
+	args := append([]memory.Loc{recv}, site.args[1:]...)
+	p.callFunc(site.instr, p.funcFor(fn), site.dst, args)
+	return true
+}
+
+// addOpaquePointees makes the opaque interface and function values
+// point to p.unknown, which is neither a box of known type nor a
+// function.  So the values which may come from opaque ones, through
+// memory or control flow, may hold unknown values as well.
+//
+// The locations of objects.Funcs, which point to themselves, are
+// function values only for the function they represent.
+func (p *T) addOpaquePointees() {
+	mdl := p.buildr.Memory()
+	ts := p.buildr.TypeSet()
+	n := mdl.Len()
+	for i := 0; i < n; i++ {
+		m := memory.Loc(i)
+		if p.opaquePts[m] || !mdl.Attrs(m).IsOpaque() {
+			continue
+		}
+		switch ts.Kind(mdl.Type(m)) {
+		case typeset.Interface:
+		case typeset.Func:
+			if _, ok := p.buildr.Object(m).(*objects.Func); ok {
+				continue
+			}
+		default:
+			continue
+		}
+		if p.unknown == memory.NoLoc {
+			p.buildr.Pos(token.NoPos).GoType(types.Typ[types.UnsafePointer])
+			p.unknown = p.buildr.Class(memory.Heap).Attrs(memory.IsOpaque).Gen()
+		}
+		p.opaquePts[m] = true
+		p.buildr.AddAddressOf(m, p.unknown)
+	}
+}
+
+// markOpaque marks the region of m as opaque.
+func (p *T) markOpaque(m memory.Loc) {
+	if m == memory.NoLoc {
+		return
+	}
+	mdl := p.buildr.Memory()
+	end := m + memory.Loc(mdl.Lsize(m))
+	for c := m; c < end; c++ {
+		mdl.AddAttrs(c, memory.IsOpaque)
+	}
+}
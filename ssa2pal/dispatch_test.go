@@ -0,0 +1,161 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/go-air/pal/results"
+	"golang.org/x/tools/go/ssa"
+)
+
+const dispatchSrc = `package p
+
+type I interface{ Get() *int }
+
+type A struct{}
+
+type B struct{}
+
+var X, Y int
+var P, Q, R *int
+
+func (A) Get() *int { return &X }
+
+func (*B) Get() *int { return &Y }
+
+func get(i I) *int { return i.Get() }
+
+func mkB() I { return &B{} }
+
+var mk = mkB
+
+func init() {
+	P = get(A{})
+	Q = get(mk())
+}
+
+func Call(i I) { R = i.Get() }
+`
+
+// inFunc gives whether the call c, which may be a copy, is in the
+// package function fn.
+func inFunc(p *T, fn string, c *ssa.CallCommon) bool {
+	for _, blk := range p.pkg.Func(fn).Blocks {
+		for _, instr := range blk.Instrs {
+			if call, ok := instr.(ssa.CallInstruction); ok && call.Common().Pos() == c.Pos() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestInvoke(t *testing.T) {
+	for name, vs := range domains {
+		res, _ := results.New()
+		p := newT(t, token.NewFileSet(), importer{}, res, dispatchSrc, vs())
+		if err := p.genMembers(); err != nil {
+			t.Fatal(err)
+		}
+		p.solve()
+		// get is called with an A, and then with a *B once the call
+		// through mk is resolved, when the call of A.Get is resolved
+		// again.
+		for _, site := range p.invokes {
+			if inFunc(p, "get", site.call) && len(site.done) != 2 {
+				t.Errorf("%s: get calls %v", name, site.done)
+			}
+		}
+		if p.resolveDynamic() {
+			t.Errorf("%s: resolved again", name)
+		}
+		p.putResults()
+		pr := p.pkgres
+		pr.MemModel.SolveIncremental()
+		for _, v := range []string{"P", "Q"} {
+			if !mayPoint(t, pr, v, "X") || !mayPoint(t, pr, v, "Y") {
+				t.Errorf("%s: %s -> %v", name, v, pointees(t, pr, v))
+			}
+		}
+	}
+}
+
+func TestInvokeOpaque(t *testing.T) {
+	for name, vs := range domains {
+		p := buildT(t, dispatchSrc, vs())
+		pr := p.pkgres
+		// i is unknown in Call, so the methods of both A and *B
+		// are called.
+		if !mayPoint(t, pr, "R", "X") || !mayPoint(t, pr, "R", "Y") {
+			t.Errorf("%s: R -> %v", name, pointees(t, pr, "R"))
+		}
+		for _, site := range p.invokes {
+			if inFunc(p, "Call", site.call) && !site.opaque {
+				t.Errorf("%s: site in Call not opaque", name)
+			}
+		}
+	}
+}
+
+func TestInvokeOpaqueFlow(t *testing.T) {
+	src := `package p
+
+type I interface{ Get() *int }
+
+type A struct{}
+
+type B struct{}
+
+type holder struct{ i I }
+
+var X, Y int
+var Local, Field, Phi *int
+
+func (A) Get() *int { return &X }
+
+func (B) Get() *int { return &Y }
+
+func ViaLocal(i I) {
+	h := new(I)
+	*h = i
+	Local = (*h).Get()
+}
+
+func ViaField(i I) {
+	h := &holder{i: i}
+	Field = h.i.Get()
+}
+
+func ViaPhi(i I, b bool) {
+	var j I = A{}
+	if b {
+		j = i
+	}
+	Phi = j.Get()
+}
+`
+	for name, vs := range domains {
+		pr := build(t, src, vs())
+		// i is unknown, so the methods of both A and B are called
+		// whichever way it reaches the invoke.
+		for _, v := range []string{"Local", "Field", "Phi"} {
+			if !mayPoint(t, pr, v, "X") || !mayPoint(t, pr, v, "Y") {
+				t.Errorf("%s: %s -> %v", name, v, pointees(t, pr, v))
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file provides support for type assertions on interface values,
+// whose boxes are given by objects.Interface.
+
+package ssa2pal
+
+import (
+	"go/types"
+
+	"github.com/go-air/pal/memory"
+	"github.com/go-air/pal/objects"
+	"golang.org/x/tools/go/ssa"
+)
+
+// assertSite represents a type assertion.
+type assertSite struct {
+	x      memory.Loc
+	ty     types.Type
+	dst    memory.Loc
+	done   map[memory.Loc]bool
+	opaque bool
+}
+
+func (p *T) typeAssert(i9n *ssa.TypeAssert) {
+	dst := p.vmap[i9n]
+	if i9n.CommaOk {
+		dst = p.buildr.Object(dst).(*objects.Tuple).At(0)
+	}
+	p.asserts = append(p.asserts, &assertSite{
+		x:    p.vmap[i9n.X],
+		ty:   i9n.AssertedType,
+		dst:  dst,
+		done: make(map[memory.Loc]bool)})
+}
+
+// resolveAssert adds the flow from the boxes in the points-to set of
+// the interface of site whose dynamic types satisfy the assertion and
+// which have not yet been considered.  If the type of a box is not
+// known, as for values which may come from opaque ones, the result
+// is opaque.
+func (p *T) resolveAssert(site *assertSite) bool {
+	mdl := p.buildr.Memory()
+	res := false
+	unknown := mdl.Attrs(site.x).IsOpaque()
+	ity, isIface := site.ty.Underlying().(*types.Interface)
+	for _, box := range mdl.PointsToFor(nil, site.x) {
+		if box == mdl.Zero() || site.done[box] {
+			continue
+		}
+		site.done[box] = true
+		ty, ok := p.boxTypes[box]
+		if !ok || mdl.Attrs(box).IsOpaque() {
+			unknown = true
+			continue
+		}
+		switch {
+		case isIface && types.Implements(ty, ity):
+			p.buildr.AddAddressOf(site.dst, box)
+		case !isIface && types.Identical(ty, site.ty):
+			p.buildr.AddTransfer(site.dst, box)
+		default:
+			continue
+		}
+		res = true
+	}
+	if unknown && !site.opaque {
+		site.opaque = true
+		p.markOpaque(site.dst)
+		res = true
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import "testing"
+
+func TestAssertOpaque(t *testing.T) {
+	src := `package p
+
+type I interface{ Get() *int }
+
+type A struct{}
+
+type holder struct{ i I }
+
+var X int
+var Local, Field *int
+
+func (A) Get() *int { return &X }
+
+func ViaLocal(e interface{}) {
+	h := new(interface{})
+	*h = e
+	Local = (*h).(I).Get()
+}
+
+func ViaField(e interface{}) {
+	h := &holder{i: e.(I)}
+	Field = h.i.Get()
+}
+`
+	for name, vs := range domains {
+		p := buildT(t, src, vs())
+		// e is unknown, so are the results of the assertions, and
+		// the method of A is called with an unknown receiver.
+		for i, site := range p.asserts {
+			if !site.opaque {
+				t.Errorf("%s: assertion %d not opaque", name, i)
+			}
+		}
+		for _, v := range []string{"Local", "Field"} {
+			if !mayPoint(t, p.pkgres, v, "X") {
+				t.Errorf("%s: %s -> %v", name, v, pointees(t, p.pkgres, v))
+			}
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file maps integer valued ssa.Values to indexing.I values.
+
+package ssa2pal
+
+import (
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math"
+
+	"github.com/go-air/pal/indexing"
+	"golang.org/x/tools/go/ssa"
+)
+
+// lenKey identifies the value of len(x) or cap(x).
+type lenKey struct {
+	x       ssa.Value
+	builtin string
+}
+
+// indexValue gives the indexing.I for the integer valued v.
+//
+// Arithmetic is mapped to the indexing domain only for signed 64 bit
+// integers, whose arithmetic wraps like that of the indexing domains.
+// Other integer values are bounded by their type.
+func (p *T) indexValue(v ssa.Value) indexing.I {
+	if i, ok := p.ivmap[v]; ok {
+		return i
+	}
+	i := p.genIndexValue(v)
+	p.ivmap[v] = i
+	return i
+}
+
+func (p *T) genIndexValue(v ssa.Value) indexing.I {
+	idx := p.indexing
+	if c, ok := v.(*ssa.Const); ok {
+		if c.Value == nil {
+			return idx.Zero()
+		}
+		if i, ok := constant.Int64Val(constant.ToInt(c.Value)); ok {
+			return idx.FromInt64(i)
+		}
+		return idx.Var()
+	}
+	if !p.isInt64(v.Type()) {
+		return p.typeRange(v.Type())
+	}
+	switch v := v.(type) {
+	case *ssa.BinOp:
+		return p.binOpIndex(v)
+	case *ssa.UnOp:
+		switch v.Op {
+		case token.SUB:
+			return idx.Times(idx.FromInt64(-1), p.indexValue(v.X))
+		case token.XOR:
+			return idx.Bnot(p.indexValue(v.X))
+		}
+	case *ssa.Convert:
+		if p.preservesValue(v.X.Type()) {
+			return p.indexValue(v.X)
+		}
+	case *ssa.ChangeType:
+		return p.indexValue(v.X)
+	case *ssa.Phi:
+		blk := v.Block()
+		// in irreducible loops, no predecessor is dominated by
+		// blk, and the edges may depend on v.
+		p.ivmap[v] = idx.Var()
+		var res indexing.I
+		for i, x := range v.Edges {
+			if blk.Dominates(blk.Preds[i]) {
+				// loop header, back edge.
+				return idx.Var()
+			}
+			if res == nil {
+				res = p.indexValue(x)
+				continue
+			}
+			res = idx.Join(res, p.indexValue(x))
+		}
+		if res != nil {
+			return res
+		}
+	case *ssa.Call:
+		return p.callIndex(v)
+	}
+	return idx.Var()
+}
+
+func (p *T) binOpIndex(v *ssa.BinOp) indexing.I {
+	idx := p.indexing
+	x, y := p.indexValue(v.X), p.indexValue(v.Y)
+	or := func(x, y indexing.I) indexing.I {
+		return idx.Bnot(idx.Band(idx.Bnot(x), idx.Bnot(y)))
+	}
+	var res indexing.I
+	switch v.Op {
+	case token.ADD:
+		res = idx.Plus(x, y)
+	case token.SUB:
+		res = idx.Plus(x, idx.Times(idx.FromInt64(-1), y))
+	case token.MUL:
+		res = idx.Times(x, y)
+	case token.QUO:
+		res, _ = idx.Div(x, y)
+	case token.REM:
+		res, _ = idx.Rem(x, y)
+	case token.AND:
+		res = idx.Band(x, y)
+	case token.OR:
+		res = or(x, y)
+	case token.XOR:
+		res = idx.Band(or(x, y), idx.Bnot(idx.Band(x, y)))
+	case token.AND_NOT:
+		res = idx.Band(x, idx.Bnot(y))
+	case token.SHL:
+		res, _ = idx.Lshift(x, y)
+	case token.SHR:
+		res, _ = idx.Rshift(x, y)
+	default:
+		res = idx.Var()
+	}
+	return res
+}
+
+// callIndex gives the index value of the results of the builtins
+// len and cap.  The length of a slice of an array is given by the
+// bounds of the slice operation.  The length and capacity of other
+// slices and strings, which are immutable, are shared between calls.
+func (p *T) callIndex(v *ssa.Call) indexing.I {
+	idx := p.indexing
+	b, ok := v.Call.Value.(*ssa.Builtin)
+	if !ok || len(v.Call.Args) != 1 {
+		return idx.Var()
+	}
+	x := v.Call.Args[0]
+	switch b.Name() {
+	case "len":
+		if ms, ok := x.(*ssa.MakeSlice); ok {
+			return p.indexValue(ms.Len)
+		}
+		if c, ok := x.(*ssa.Const); ok && c.Value != nil && c.Value.Kind() == constant.String {
+			return idx.FromInt64(int64(len(constant.StringVal(c.Value))))
+		}
+		if sl, ok := x.(*ssa.Slice); ok {
+			if pt, ok := sl.X.Type().Underlying().(*types.Pointer); ok {
+				lo, hi := idx.Zero(), idx.FromInt64(pt.Elem().Underlying().(*types.Array).Len())
+				if sl.Low != nil {
+					lo = p.indexValue(sl.Low)
+				}
+				if sl.High != nil {
+					hi = p.indexValue(sl.High)
+				}
+				return idx.Plus(hi, idx.Times(idx.FromInt64(-1), lo))
+			}
+		}
+	case "cap":
+		if ms, ok := x.(*ssa.MakeSlice); ok {
+			return p.indexValue(ms.Cap)
+		}
+	default:
+		return idx.Var()
+	}
+	nonNeg := idx.Band(idx.Var(), idx.FromInt64(math.MaxInt64))
+	switch x.Type().Underlying().(type) {
+	case *types.Slice, *types.Basic:
+	default:
+		return nonNeg
+	}
+	key := lenKey{x: x, builtin: b.Name()}
+	if i, ok := p.lens[key]; ok {
+		return i
+	}
+	p.lens[key] = nonNeg
+	return nonNeg
+}
+
+// isInt64 returns whether ty is a signed 64 bit integer type.
+func (p *T) isInt64(ty types.Type) bool {
+	b, ok := ty.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+	info := b.Info()
+	return info&types.IsInteger != 0 && info&types.IsUnsigned == 0 &&
+		p.pass.TypesSizes.Sizeof(b) == 8
+}
+
+// preservesValue returns whether converting a value of type
+// ty to a signed 64 bit integer preserves its value.
+func (p *T) preservesValue(ty types.Type) bool {
+	b, ok := ty.Underlying().(*types.Basic)
+	if !ok || b.Info()&types.IsInteger == 0 {
+		return false
+	}
+	return b.Info()&types.IsUnsigned == 0 || p.pass.TypesSizes.Sizeof(b) < 8
+}
+
+// typeRange gives the index value of an integer of type ty
+// about which nothing else is known.
+func (p *T) typeRange(ty types.Type) indexing.I {
+	idx := p.indexing
+	b, ok := ty.Underlying().(*types.Basic)
+	if !ok || b.Info()&types.IsInteger == 0 || b.Info()&types.IsUnsigned == 0 {
+		return idx.Var()
+	}
+	sz := p.pass.TypesSizes.Sizeof(b)
+	if sz >= 8 {
+		return idx.Var()
+	}
+	return idx.Band(idx.Var(), idx.FromInt64(int64(1)<<uint(8*sz)-1))
+}
+
+// eltOffset gives the logical offset, relative to the first element, of
+// the element at index i of an array or slice whose elements have type elt.
+func (p *T) eltOffset(elt types.Type, i indexing.I) indexing.I {
+	ts := p.buildr.TypeSet()
+	esz := p.indexing.FromInt64(int64(ts.Lsize(ts.FromGoType(elt))))
+	return p.indexing.Times(i, esz)
+}
+
+// slice adds the constraints for the slice operation i9n, whose
+// result points to the element of i9n.X at i9n.Low.
+func (p *T) slice(i9n *ssa.Slice) {
+	res := p.vmap[i9n]
+	x := p.vmap[i9n.X]
+	off := p.indexing.Zero()
+	if i9n.Low != nil {
+		elt := i9n.Type().Underlying().(*types.Slice).Elem()
+		off = p.eltOffset(elt, p.indexAt(i9n.Block(), i9n.Low))
+	}
+	switch i9n.X.Type().Underlying().(type) {
+	case *types.Pointer: // to array
+		// first element at logical offset 1.
+		p.buildr.AddTransferIndex(res, x, p.indexing.Plus(p.indexing.One(), off))
+	case *types.Slice:
+		p.buildr.AddTransferIndex(res, x, off)
+	default:
+		// strings have no pointers.
+	}
+}
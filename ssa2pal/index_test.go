@@ -0,0 +1,52 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import "testing"
+
+func TestIndexIrreducible(t *testing.T) {
+	src := `package p
+
+var X int
+var A [16]*int
+var P *int
+
+func f(a *[16]*int, b bool) *int {
+	i := 0
+	if b {
+		goto L2
+	}
+L1:
+	i++
+	if i > 10 {
+		return a[i]
+	}
+L2:
+	i += 2
+	goto L1
+}
+
+func init() {
+	A[12] = &X
+	P = f(&A, true)
+}
+`
+	for name, vs := range domains {
+		pr := build(t, src, vs())
+		if !mayPoint(t, pr, "P", "X") {
+			t.Errorf("%s: P does not point to X: %v", name, pointees(t, pr, "P"))
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import "testing"
+
+func TestPanicRecover(t *testing.T) {
+	src := `package p
+
+var X, Y int
+var P *int
+
+func thrower() { panic(&X) }
+
+func catch() (r *int) {
+	defer func() {
+		if v := recover(); v != nil {
+			r = v.(*int)
+		}
+	}()
+	thrower()
+	return &Y
+}
+
+func init() { P = catch() }
+`
+	for name, vs := range domains {
+		pr := build(t, src, vs())
+		// the value passed to panic in thrower is recovered in the
+		// deferred closure of catch.
+		if !mayPoint(t, pr, "P", "X") || !mayPoint(t, pr, "P", "Y") {
+			t.Errorf("%s: P -> %v", name, pointees(t, pr, "P"))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file provides path sensitive refinement of index values
+// from the conditions of ssa.If instructions.
+
+package ssa2pal
+
+import (
+	"go/token"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/xtruth"
+	"golang.org/x/tools/go/ssa"
+)
+
+// fact represents the condition a < b, or a <= b if !strict, which
+// holds in some block.
+type fact struct {
+	a, b   ssa.Value
+	strict bool
+}
+
+// SetRefine sets whether p refines index values in blocks dominated
+// by an edge of an ssa.If, in the manner of static single information
+// form.  The indices of ssa.Index and ssa.IndexAddr and the low
+// bounds of ssa.Slice are refined, see indexAt.  It must be called
+// before GenResult.
+func (p *T) SetRefine(on bool) {
+	p.refine = on
+}
+
+// blockFacts returns the facts which hold on entry to blk by virtue of
+// the edges from ssa.If instructions which dominate it.
+func (p *T) blockFacts(blk *ssa.BasicBlock) []fact {
+	if fs, ok := p.facts[blk]; ok {
+		return fs
+	}
+	var fs []fact
+	if idom := blk.Idom(); idom != nil {
+		fs = p.blockFacts(idom)
+	}
+	if len(blk.Preds) == 1 {
+		fs = append(fs[:len(fs):len(fs)], p.edgeFacts(blk.Preds[0], blk)...)
+	}
+	p.facts[blk] = fs
+	return fs
+}
+
+// edgeFacts gives the facts which hold on the edge from pred to blk,
+// where blk has only pred as predecessor.
+func (p *T) edgeFacts(pred, blk *ssa.BasicBlock) []fact {
+	n := len(pred.Instrs)
+	if n == 0 || len(pred.Succs) != 2 || pred.Succs[0] == pred.Succs[1] {
+		return nil
+	}
+	iff, ok := pred.Instrs[n-1].(*ssa.If)
+	if !ok {
+		return nil
+	}
+	cond, ok := iff.Cond.(*ssa.BinOp)
+	if !ok || !p.preservesValue(cond.X.Type()) {
+		return nil
+	}
+	op := cond.Op
+	if blk == pred.Succs[1] {
+		op = negate(op)
+	}
+	x, y := cond.X, cond.Y
+	switch op {
+	case token.LSS:
+		return []fact{{x, y, true}}
+	case token.LEQ:
+		return []fact{{x, y, false}}
+	case token.GTR:
+		return []fact{{y, x, true}}
+	case token.GEQ:
+		return []fact{{y, x, false}}
+	case token.EQL:
+		return []fact{{x, y, false}, {y, x, false}}
+	}
+	return nil
+}
+
+func negate(op token.Token) token.Token {
+	switch op {
+	case token.LSS:
+		return token.GEQ
+	case token.LEQ:
+		return token.GTR
+	case token.GTR:
+		return token.LEQ
+	case token.GEQ:
+		return token.LSS
+	case token.EQL:
+		return token.NEQ
+	case token.NEQ:
+		return token.EQL
+	}
+	return token.ILLEGAL
+}
+
+// indexAt gives the index value of the integer valued v used in
+// blk.  If p refines index values, this is the value of v bounded by
+// the facts which hold in blk, otherwise it is that of indexValue.
+func (p *T) indexAt(blk *ssa.BasicBlock, v ssa.Value) indexing.I {
+	idx := p.indexing
+	iv := p.indexValue(v)
+	if !p.refine {
+		return iv
+	}
+	for _, f := range p.blockFacts(blk) {
+		switch v {
+		case f.a:
+			// v < f.b or v <= f.b
+			hi := p.indexValue(f.b)
+			if f.strict {
+				hi = idx.Plus(hi, idx.FromInt64(-1))
+			}
+			iv = idx.Bound(iv, nil, hi)
+		case f.b:
+			// f.a < v or f.a <= v
+			lo := p.indexValue(f.a)
+			if f.strict {
+				lo = idx.Plus(lo, idx.One())
+			}
+			iv = idx.Bound(iv, lo, nil)
+		}
+	}
+	return iv
+}
+
+// inRange returns whether the integer value i, used in blk, is
+// provably in [0, n).
+func (p *T) inRange(blk *ssa.BasicBlock, i ssa.Value, n int64) bool {
+	idx := p.indexing
+	iv := p.indexAt(blk, i)
+	lo := idx.Less(iv, idx.Zero()) == xtruth.False
+	hi := idx.Less(iv, idx.FromInt64(n)) == xtruth.True
+	if lo && hi || !p.refine {
+		return lo && hi
+	}
+	same := func(v ssa.Value) bool {
+		return v == i || idx.Equal(p.indexValue(v), iv) == xtruth.True
+	}
+	for _, f := range p.blockFacts(blk) {
+		switch {
+		case same(f.b):
+			// f.a < i or f.a <= i, so 0 <= i if -1 <= f.a
+			// or 0 <= f.a respectively.
+			t := int64(0)
+			if f.strict {
+				t = -1
+			}
+			if idx.Less(p.indexValue(f.a), idx.FromInt64(t)) == xtruth.False {
+				lo = true
+			}
+		case same(f.a):
+			// i < f.b or i <= f.b, so i < n if f.b <= n
+			// or f.b <= n-1 respectively.
+			t := n
+			if !f.strict {
+				t = n - 1
+			}
+			if idx.Less(idx.FromInt64(t), p.indexValue(f.b)) == xtruth.False {
+				hi = true
+			}
+		}
+	}
+	return lo && hi
+}
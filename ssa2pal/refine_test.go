@@ -0,0 +1,90 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+func TestRefineInRange(t *testing.T) {
+	src := `package p
+
+func arr() [4]*int { return [4]*int{} }
+
+func f(i int) *int {
+	if 0 <= i && i < 4 {
+		return arr()[i]
+	}
+	return nil
+}
+`
+	for name, vs := range domains {
+		for _, refine := range []bool{false, true} {
+			p := buildT(t, src, vs(), func(p *T) { p.SetRefine(refine) })
+			var ix *ssa.Index
+			for _, blk := range p.pkg.Func("f").Blocks {
+				for _, instr := range blk.Instrs {
+					if v, ok := instr.(*ssa.Index); ok {
+						ix = v
+					}
+				}
+			}
+			if ix == nil {
+				t.Fatal("no ssa.Index")
+			}
+			for n, exp := range map[int64]bool{3: false, 4: refine, 5: refine} {
+				if got := p.inRange(ix.Block(), ix.Index, n); got != exp {
+					t.Errorf("%s refine=%t: %s in [0, %d) %t, expected %t", name, refine, ix.Index, n, got, exp)
+				}
+			}
+		}
+	}
+}
+
+func TestRefineSlice(t *testing.T) {
+	src := `package p
+
+var X, Y int
+var A [4]*int
+var P, Q *int
+
+func f(i int) {
+	s := A[:2]
+	if 0 <= i && i < len(s) {
+		P = s[i]
+		Q = A[i]
+	}
+}
+
+func init() {
+	A[0] = &X
+	A[3] = &Y
+}
+`
+	for name, vs := range domains {
+		for _, refine := range []bool{false, true} {
+			pr := build(t, src, vs(), func(p *T) { p.SetRefine(refine) })
+			// consts cannot represent i in [0, 2).
+			exact := refine && name != "consts"
+			for _, v := range []string{"P", "Q"} {
+				if !mayPoint(t, pr, v, "X") || mayPoint(t, pr, v, "Y") == exact {
+					t.Errorf("%s refine=%t: %s -> %v", name, refine, v, pointees(t, pr, v))
+				}
+			}
+		}
+	}
+}
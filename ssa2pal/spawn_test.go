@@ -0,0 +1,80 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import (
+	"testing"
+
+	"github.com/go-air/pal/memory"
+)
+
+func TestSpawn(t *testing.T) {
+	src := `package p
+
+var X int
+
+type box struct{ p *int }
+
+func worker(b *box) {}
+
+func init() {
+	b := &box{p: &X}
+	go worker(b)
+	go func() { worker(b) }()
+	c := &box{}
+	go worker(c)
+}
+`
+	for name, vs := range domains {
+		pr := build(t, src, vs())
+		mdl := pr.MemModel
+		if len(pr.Spawns) != 3 {
+			t.Fatalf("%s: spawns %v", name, pr.Spawns)
+		}
+		// boxes[i] holds the values passed to goroutine i, and
+		// reach[i] the locations reachable from them.
+		boxes := make([]map[memory.Loc]bool, len(pr.Spawns))
+		reach := make([]map[memory.Loc]bool, len(pr.Spawns))
+		for i, g := range pr.Spawns {
+			args := mdl.PointsToFor(nil, g)
+			if len(args) != 1 {
+				t.Errorf("%s: spawn %d args %v", name, i, args)
+			}
+			boxes[i] = make(map[memory.Loc]bool)
+			for _, a := range args {
+				for _, b := range mdl.PointsToFor(nil, a) {
+					boxes[i][b] = true
+				}
+			}
+			reach[i] = make(map[memory.Loc]bool)
+			for _, r := range mdl.Reachable(nil, g) {
+				reach[i][r] = true
+			}
+		}
+		if !reach[0][mdl.Root(object(t, pr, "X"))] {
+			t.Errorf("%s: X not reachable from %d", name, pr.Spawns[0])
+		}
+		// only the box b is handed to two goroutines, directly to
+		// the first, and through the variable bound by the closure
+		// to the second.
+		sh := pr.Shared()
+		if len(sh) != 1 {
+			t.Fatalf("%s: shared %v", name, sh)
+		}
+		if !boxes[0][sh[0]] || !reach[1][sh[0]] || boxes[1][sh[0]] || reach[2][sh[0]] {
+			t.Errorf("%s: shared %v boxes %v", name, sh, boxes)
+		}
+	}
+}
@@ -51,19 +51,59 @@ type T struct {
 	buildr *objects.Builder
 
 	funcs map[*ssa.Function]*objects.Func
+	// functions whose bodies are to be translated, see funcFor
+	pending []*ssa.Function
+	// dynamic call sites and type assertions, see solve
+	invokes []*invokeSite
+	fcalls  []*funcSite
+	asserts []*assertSite
+	// goroutine spawn sites, see spawn
+	spawns []memory.Loc
+	// types of interface boxes, see MakeInterface
+	boxTypes map[memory.Loc]types.Type
+	// the unknown value of opaque interface and function
+	// values, see addOpaquePointees
+	unknown   memory.Loc
+	opaquePts map[memory.Loc]bool
+
+	// index values of integer ssa.Values, see indexValue
+	ivmap map[ssa.Value]indexing.I
+	lens  map[lenKey]indexing.I
+
+	// refinement of index values, see SetRefine
+	refine bool
+	facts  map[*ssa.BasicBlock][]fact
+
+	// conservative unsafe.Pointer modelling, see SetUnsafe
+	unsafe      bool
+	unsafes     []memory.Loc
+	unsafeSites []*unsafeSite
+
+	// imports maps import paths to the relocation of
+	// the imported package's locs in buildr.Memory()
+	imports map[string][]memory.Loc
 }
 
 func New(pass *analysis.Pass, vs indexing.T) (*T, error) {
 	palres := pass.Analyzer.FactTypes[0].(*results.T)
 	pkgPath := pass.Pkg.Path()
 	pkgRes := results.NewPkgRes(pkgPath, vs)
-	for _, imp := range pass.Pkg.Imports() {
+	imports := pass.Pkg.Imports()
+	iPaths := make([]string, 0, len(imports))
+	for _, imp := range imports {
+		if imp == types.Unsafe {
+			// no pal results.
+			continue
+		}
 		iPath := imp.Path()
 		//fmt.Printf("\t%s: importing %s\n", pkgPath, iPath)
 		if palres.Lookup(iPath) == nil {
 			return nil, fmt.Errorf("couldn't find pal results for %s\n", iPath)
 		}
+		iPaths = append(iPaths, iPath)
 	}
+	// sort for determinism
+	sort.Strings(iPaths)
 
 	ssapkg := pass.ResultOf[buildssa.Analyzer].(*buildssa.SSA)
 	ssapkg.Pkg.Build()
@@ -78,7 +118,18 @@ func New(pass *analysis.Pass, vs indexing.T) (*T, error) {
 		buildr:   objects.NewBuilder(pkgPath, vs),
 		vmap:     make(map[ssa.Value]memory.Loc, 8192),
 
-		funcs: make(map[*ssa.Function]*objects.Func)}
+		funcs:     make(map[*ssa.Function]*objects.Func),
+		ivmap:     make(map[ssa.Value]indexing.I),
+		boxTypes:  make(map[memory.Loc]types.Type),
+		opaquePts: make(map[memory.Loc]bool),
+		lens:      make(map[lenKey]indexing.I),
+		facts:     make(map[*ssa.BasicBlock][]fact),
+		imports:   make(map[string][]memory.Loc, len(iPaths))}
+	for _, iPath := range iPaths {
+		ipkg := palres.Lookup(iPath)
+		tperm := pal.buildr.TypeSet().Import(ipkg.TypeSet)
+		pal.imports[iPath] = pal.buildr.Memory().Import(ipkg.MemModel, tperm, []memory.Loc{})
+	}
 	return pal, nil
 }
 
@@ -86,13 +137,25 @@ func (p *T) GenResult() (*results.T, error) {
 	if tracePackage {
 		fmt.Printf("ssa2pal translating %s\n", p.pass.Pkg.Path())
 	}
+	if err := p.genMembers(); err != nil {
+		return nil, err
+	}
+	p.solve()
+
+	// place the results for current package in p.results.
+	p.putResults()
+	return p.results, nil
+}
+
+// genMembers translates the globals, functions and methods of the
+// package.
+func (p *T) genMembers() error {
 	var err error
 	mbrs := p.ssa.Pkg.Members
 	mbrKeys := make([]string, 0, len(mbrs))
 	// get and sort relevant member keys for determinism
 	for name, mbr := range mbrs {
-		switch mbr.Token() {
-		case token.TYPE, token.CONST:
+		if mbr.Token() == token.CONST {
 			continue
 		}
 		mbrKeys = append(mbrKeys, name)
@@ -107,21 +170,21 @@ func (p *T) GenResult() (*results.T, error) {
 		}
 	}
 
-	// add funcs
+	// add funcs and methods
 	for _, name := range mbrKeys {
 		mbr := mbrs[name]
-		switch fn := mbr.(type) {
+		switch mbr := mbr.(type) {
 		case *ssa.Function:
-			if err = p.addFuncDecl(name, fn); err != nil {
-				return nil, err
+			if err = p.addFuncDecl(name, mbr); err != nil {
+				return err
+			}
+		case *ssa.Type:
+			if _, ok := mbr.Type().Underlying().(*types.Interface); !ok {
+				p.addMethods(mbr.Type())
 			}
 		}
 	}
-	// TBD: calc results from generation above
-
-	// place the results for current package in p.results.
-	p.putResults()
-	return p.results, nil
+	return nil
 }
 
 func (p *T) genGlobal(name string, x *ssa.Global) {
@@ -148,11 +211,26 @@ func (p *T) genGlobal(name string, x *ssa.Global) {
 }
 
 func (p *T) addFuncDecl(name string, fn *ssa.Function) error {
+	p.funcFor(fn)
+	p.genPending()
+	return nil
+}
+
+// funcFor returns the objects.Func for fn, declaring it if it has not
+// been declared.  The body of fn is translated by genPending.
+func (p *T) funcFor(fn *ssa.Function) *objects.Func {
+	if memFn, ok := p.funcs[fn]; ok {
+		return memFn
+	}
+	name := fn.Name()
+	if fn.Signature.Recv() != nil || fn.Parent() != nil {
+		name = fn.RelString(p.pkg.Pkg)
+	}
 	if traceFunc {
-		fmt.Printf("ssa2pal adding \"%s\".%s\n", p.pass.Pkg.Path(), fn.Name())
+		fmt.Printf("ssa2pal adding \"%s\".%s\n", p.pass.Pkg.Path(), name)
 	}
 	opaque := memory.NoAttrs
-	if token.IsExported(name) {
+	if fn.Parent() == nil && token.IsExported(fn.Name()) {
 		opaque = memory.IsOpaque
 	}
 	memFn := p.buildr.Func(fn.Signature, name, opaque)
@@ -160,19 +238,53 @@ func (p *T) addFuncDecl(name string, fn *ssa.Function) error {
 	p.vmap[fn] = memFn.Loc()
 	fmt.Printf("built func %s at %d\n", name, memFn.Loc())
 
-	for i, param := range fn.Params {
+	params := fn.Params
+	if fn.Signature.Recv() != nil {
+		p.vmap[params[0]] = p.buildr.Memory().Obj(memFn.RecvLoc(0))
+		params = params[1:]
+	}
+	for i, param := range params {
 		p.vmap[param] = p.buildr.Memory().Obj(memFn.ParamLoc(i))
 		if traceParam {
 			fmt.Printf("setting param %s to %d\n", param, p.vmap[param])
 		}
 	}
-	// free vars not needed here -- top level func def
-
+	for _, fv := range fn.FreeVars {
+		p.vmap[fv] = p.buildr.Memory().Obj(p.buildr.AddFree(memFn, fv.Type()))
+	}
 	p.funcs[fn] = memFn
-	p.genBlocksValues(name, fn)
-	p.genConstraints(name, fn)
+	if fn.Blocks != nil {
+		p.pending = append(p.pending, fn)
+	}
+	return memFn
+}
 
-	return nil
+// genPending translates the bodies of the functions declared
+// by funcFor.
+func (p *T) genPending() {
+	for len(p.pending) > 0 {
+		fn := p.pending[0]
+		p.pending = p.pending[1:]
+		name := p.funcs[fn].Name()
+		p.genBlocksValues(name, fn)
+		p.genConstraints(name, fn)
+	}
+}
+
+// addMethods declares the methods declared in the package for the
+// named type ty.
+func (p *T) addMethods(ty types.Type) {
+	prog := p.pkg.Prog
+	for _, t := range [...]types.Type{ty, types.NewPointer(ty)} {
+		mset := prog.MethodSets.MethodSet(t)
+		for i := 0; i < mset.Len(); i++ {
+			fn := prog.MethodValue(mset.At(i))
+			if fn != nil && fn.Pkg == p.pkg {
+				p.funcFor(fn)
+			}
+		}
+	}
+	p.genPending()
 }
 
 func (p *T) genBlocksValues(name string, fn *ssa.Function) {
@@ -244,14 +356,14 @@ func (p *T) genValueLoc(v ssa.Value) memory.Loc {
 	switch v := v.(type) {
 	case *ssa.Alloc:
 		if v.Heap {
-			p.buildr.Class(memory.Global)
+			p.buildr.Class(memory.Heap)
 		}
 		p.buildr.GoType(v.Type().Underlying().(*types.Pointer).Elem())
 		_, res = p.buildr.WithPointer()
 	case *ssa.MakeSlice:
 		res = p.buildr.Slice(v.Type().Underlying().(*types.Slice),
-			p.indexing.Var(),
-			p.indexing.Var()).Loc()
+			p.indexValue(v.Len),
+			p.indexValue(v.Cap)).Loc()
 	case *ssa.MakeMap:
 		res = p.buildr.Map(v.Type().Underlying().(*types.Map)).Loc()
 
@@ -290,7 +402,7 @@ func (p *T) genValueLoc(v ssa.Value) memory.Loc {
 				// we
 				//  1. take address of the array, call it pa, in a new loc
 				//  2. create qa, same type as pa
-				//  3. add AddTransferIndex(qa, pa, p.indexing.Var())
+				//  3. add AddTransferIndex(qa, pa, offset of v.Index)
 				//  4. create res, type of element of array
 				//  5. create res = load(qa)
 				ty, ok := v.X.Type().Underlying().(*types.Array)
@@ -303,10 +415,12 @@ func (p *T) genValueLoc(v ssa.Value) memory.Loc {
 				p.buildr.AddAddressOf(pelt, x.At(0))
 				qelt := p.buildr.Gen()
 				// it may crash if oob, add address of nil
-				// TBD: see if with indexing we can constrain this.
-				p.buildr.AddAddressOf(qelt, p.buildr.Memory().Zero())
+				if !p.inRange(v.Block(), v.Index, int64(x.Len())) {
+					p.buildr.AddAddressOf(qelt, p.buildr.Memory().Zero())
+				}
 				res = p.buildr.GoType(eltTy).Gen()
-				p.buildr.AddTransferIndex(qelt, pelt, p.indexing.Var())
+				off := p.eltOffset(eltTy, p.indexAt(v.Block(), v.Index))
+				p.buildr.AddTransferIndex(qelt, pelt, off)
 				p.buildr.AddLoad(res, qelt)
 			}
 		}
@@ -363,11 +477,44 @@ func (p *T) genValueLoc(v ssa.Value) memory.Loc {
 			p.buildr.AddLoad(res, p.vmap[v.X])
 		case token.ARROW:
 			c := p.buildr.Object(p.vmap[v.X]).(*objects.Chan)
+			if v.CommaOk {
+				c.Recv(p.buildr.Object(res).(*objects.Tuple).At(0), p.buildr.Memory())
+				break
+			}
 			c.Recv(res, p.buildr.Memory())
 
 		default: // indexing
 		}
 
+	case *ssa.Global:
+		res = p.buildr.FromGoType(v.Type())
+		p.bindImport(v.Pkg, symbol(v), res)
+	case *ssa.Function:
+		if v.Blocks != nil {
+			// defined in this package, or synthetic
+			res = p.funcFor(v).Loc()
+			break
+		}
+		res = p.buildr.FromGoType(v.Type())
+		if v.Parent() == nil {
+			p.bindImport(v.Pkg, symbol(v), res)
+		}
+	case *ssa.MakeClosure:
+		fn := p.funcFor(v.Fn.(*ssa.Function))
+		res = p.buildr.FromGoType(v.Type())
+		p.buildr.AddTransfer(res, fn.Loc())
+		free := make([]memory.Loc, len(v.Bindings))
+		for i, b := range v.Bindings {
+			free[i] = p.vmap[b]
+		}
+		p.buildr.Bind(fn, free)
+	case *ssa.MakeInterface:
+		ity := v.Type().Underlying().(*types.Interface)
+		iface := p.buildr.MakeInterface(ity, v.X.Type(), p.vmap[v.X])
+		p.boxTypes[iface.Concrete] = v.X.Type()
+		res = iface.Loc()
+	case *ssa.Convert:
+		res = p.convert(v)
 	default:
 		res = p.buildr.FromGoType(v.Type())
 
@@ -395,21 +542,27 @@ func (p *T) genI9nConstraints(fnName string, i9n ssa.Instruction) error {
 	}
 	switch i9n := i9n.(type) {
 	case *ssa.Alloc: // done in gen locs
-	case *ssa.BinOp: // tbd: indexing
+	case *ssa.BinOp: // integer values are done in indexValue
 		switch i9n.Op {
 		case token.ARROW:
 			panic("send binop")
 		default:
-
+			if isUintptr(i9n.Type()) {
+				p.uintptrArith(i9n)
+			}
 		}
 	case *ssa.Call:
-		p.call(i9n.Call, p.vmap[i9n])
+		p.call(i9n, p.vmap[i9n])
 	case *ssa.ChangeInterface:
+		// same boxes
+		p.buildr.AddTransfer(p.vmap[i9n], p.vmap[i9n.X])
 	case *ssa.ChangeType:
-	case *ssa.Convert:
+		// same underlying type
+		p.buildr.AddTransfer(p.vmap[i9n], p.vmap[i9n.X])
+	case *ssa.Convert: // constraints done in genLoc
 	case *ssa.DebugRef:
 	case *ssa.Defer:
-		p.call(i9n.Call, memory.NoLoc)
+		p.call(i9n, memory.NoLoc)
 	case *ssa.Extract: // done in gen locs
 	case *ssa.Field: // done in gen locs
 
@@ -429,22 +582,35 @@ func (p *T) genI9nConstraints(fnName string, i9n ssa.Instruction) error {
 			p.buildr.AddAddressOf(out, fobj)
 			mdl.SetObj(out, fobj)
 		} else {
-			mdl.AddTransferIndex(out, ptr, p.indexing.FromInt64(int64(i9n.Field)))
+			// transfer indices are logical offsets.
+			ts := p.buildr.TypeSet()
+			sty := i9n.X.Type().Underlying().(*types.Pointer).Elem()
+			_, _, loff := ts.Field(ts.FromGoType(sty.Underlying()), i9n.Field)
+			mdl.AddTransferIndex(out, ptr, p.indexing.FromInt64(int64(loff)))
 		}
 
 	case *ssa.Go:
-		// for now, treat as call
-		p.call(i9n.Call, memory.NoLoc)
+		p.spawn(i9n)
+		p.call(i9n, memory.NoLoc)
 	case *ssa.If:
 	case *ssa.Index: // constraints done in gen locs
 	case *ssa.IndexAddr:
 		ptr := p.vmap[i9n.X]
 		res := p.vmap[i9n]
+		elt := i9n.Type().Underlying().(*types.Pointer).Elem()
+		off := p.eltOffset(elt, p.indexAt(i9n.Block(), i9n.Index))
 		switch i9n.X.Type().Underlying().(type) {
 		case *types.Pointer: // to array
-			p.buildr.AddTransferIndex(res, ptr, p.indexing.Var())
+			// first element at logical offset 1, then the element
+			// at off from it.
+			p.buildr.Pos(i9n.Pos()).GoType(i9n.Type()).Class(memory.Local).Attrs(memory.NoAttrs)
+			elt0 := p.buildr.Gen()
+			p.buildr.AddTransferIndex(elt0, ptr, p.indexing.One())
+			p.buildr.AddTransferIndex(res, elt0, off)
 		case *types.Slice:
-			p.buildr.AddTransferIndex(res, ptr, p.indexing.Var())
+			// the slice points to its first element, a memory.Rel
+			// loc, see objects.Slice.
+			p.buildr.AddTransferIndex(res, ptr, off)
 		default:
 			panic("unexpected type of ssa.IndexAddr.X")
 		}
@@ -478,6 +644,7 @@ func (p *T) genI9nConstraints(fnName string, i9n ssa.Instruction) error {
 		}
 	case *ssa.Next: // handled in genLoc
 	case *ssa.Panic:
+		p.buildr.Panic(p.funcs[i9n.Parent()], p.vmap[i9n.X])
 	case *ssa.Phi:
 		v := p.vmap[i9n]
 		for _, x := range i9n.Edges {
@@ -487,7 +654,8 @@ func (p *T) genI9nConstraints(fnName string, i9n ssa.Instruction) error {
 	case *ssa.Range: // everything is in ssa.Next, see genLoc
 	case *ssa.RunDefers:
 		// no-op b/c we treat defers like calls.
-	case *ssa.Select: // all comm clauses handled via <- unary and binary ops.
+	case *ssa.Select:
+		p.selekt(i9n)
 	case *ssa.Send:
 		c := p.buildr.Object(p.vmap[i9n.Chan]).(*objects.Chan)
 		c.Send(p.vmap[i9n.X], p.buildr.Memory())
@@ -506,56 +674,139 @@ func (p *T) genI9nConstraints(fnName string, i9n ssa.Instruction) error {
 	case *ssa.UnOp:
 
 	case *ssa.Slice:
-		p.buildr.AddTransfer(p.vmap[i9n], p.vmap[i9n.X])
+		p.slice(i9n)
 	case *ssa.Store:
 		vloc := p.vmap[i9n.Val]
 		aloc := p.vmap[i9n.Addr]
 		p.buildr.AddStore(aloc, vloc)
 
 	case *ssa.TypeAssert:
+		p.typeAssert(i9n)
 	default:
 		panic("unknown ssa Instruction")
 	}
 	return nil
 }
 
+// symbol gives the name of the package member or method v in
+// results.PkgRes.Symbols: the name of the member, or the method
+// name qualified by the receiver type, such as (*T).Get.
+func symbol(v ssa.Value) string {
+	if fn, ok := v.(*ssa.Function); ok && fn.Signature.Recv() != nil {
+		return fn.RelString(fn.Pkg.Pkg)
+	}
+	return v.Name()
+}
+
+// bindImport binds the loc 'use' representing the member 'name' of the
+// imported package 'pkg' to its definition in the imported results.
+func (p *T) bindImport(pkg *ssa.Package, name string, use memory.Loc) {
+	if pkg == nil || pkg == p.pkg {
+		return
+	}
+	iPath := pkg.Pkg.Path()
+	perm, ok := p.imports[iPath]
+	if !ok {
+		return
+	}
+	def := p.results.Lookup(iPath).Lookup(name)
+	if def == memory.NoLoc {
+		return
+	}
+	p.buildr.AddTransfer(use, perm[def])
+}
+
 func (p *T) PkgPath() string {
 	return p.pass.Pkg.Path()
 }
 
-func (p *T) call(c ssa.CallCommon, dst memory.Loc) {
+// selekt adds the sends and receives of the states of a select.
+// The values received are the elements of the result tuple
+// following the index and recvOk.
+func (p *T) selekt(i9n *ssa.Select) {
+	mdl := p.buildr.Memory()
+	tuple := p.buildr.Object(p.vmap[i9n]).(*objects.Tuple)
+	r := 2
+	for _, st := range i9n.States {
+		c := p.buildr.Object(p.vmap[st.Chan]).(*objects.Chan)
+		switch st.Dir {
+		case types.SendOnly:
+			c.Send(p.vmap[st.Send], mdl)
+		case types.RecvOnly:
+			c.Recv(tuple.At(r), mdl)
+			r++
+		}
+	}
+}
+
+// spawn generates the location representing the goroutine spawn
+// site i9n, which points to the values passed to the goroutine: the
+// arguments, the receiver, and the bindings of a closure.
+func (p *T) spawn(i9n *ssa.Go) {
+	c := &i9n.Call
+	vals := c.Args
+	switch fv := c.Value.(type) {
+	case *ssa.MakeClosure:
+		vals = append(vals[:len(vals):len(vals)], fv.Bindings...)
+	case *ssa.Function, *ssa.Builtin:
+	default:
+		// interface or function value.
+		vals = append(vals[:len(vals):len(vals)], fv)
+	}
+	p.buildr.Pos(i9n.Pos()).GoType(types.Typ[types.UnsafePointer])
+	g := p.buildr.Class(memory.Global).Attrs(memory.NoAttrs).Gen()
+	for _, v := range vals {
+		if m := p.vmap[v]; m != memory.NoLoc {
+			p.buildr.AddAddressOf(g, m)
+		}
+	}
+	p.spawns = append(p.spawns, g)
+}
+
+func (p *T) call(instr ssa.CallInstruction, dst memory.Loc) {
+	c := instr.Common()
 	if c.IsInvoke() {
-		p.invoke(c)
+		p.invoke(instr, dst)
 		return
 	}
-	callee := c.StaticCallee()
-	if false && callee == nil {
-		fmt.Printf("dst %d not static %#v\n", dst, c)
-		return
+	args := make([]memory.Loc, len(c.Args))
+	for i, argVal := range c.Args {
+		args[i] = p.vmap[argVal]
 	}
 	switch fssa := c.Value.(type) {
 	case *ssa.Builtin:
+		p.builtin(fssa, instr, dst, args)
 	case *ssa.MakeClosure:
-	default: // eg *Function (static call)
-		// dynamic dispatch
+		// the free variables are bound by the closure, see genValueLoc.
+		p.callFunc(instr, p.funcFor(fssa.Fn.(*ssa.Function)), dst, args)
+	case *ssa.Function: // static call
+		p.callFunc(instr, p.buildr.Object(p.vmap[fssa]).(*objects.Func), dst, args)
+	case *ssa.Const:
+		// nil function value: the call panics and has no targets.
+	default:
 		floc := p.vmap[fssa]
 		if floc == memory.NoLoc {
 			panic("wilma!")
 		}
-		fn, ok := p.buildr.Object(floc).(*objects.Func)
-		if !ok {
-			fmt.Printf(" could not call '%s' loc %d type %s\n", fssa.Name(), floc, p.buildr.TypeSet().String(p.buildr.Memory().Type(floc)))
-			return
-		}
-		args := make([]memory.Loc, len(c.Args))
-		for i, argVal := range c.Args {
-			args[i] = p.vmap[argVal]
-		}
-		p.buildr.Call(fn, dst, args)
+		// function value, see resolveFuncCall.
+		p.callValue(instr, floc, dst, args)
 	}
 }
 
-func (p *T) invoke(c ssa.CallCommon) {
+// callFunc calls fn at instr.  Unless instr is a go statement, the
+// panics of fn propagate to the caller.  If instr is a defer
+// statement, then the panics of the caller propagate to fn, where
+// they may be recovered.
+func (p *T) callFunc(instr ssa.CallInstruction, fn *objects.Func, dst memory.Loc, args []memory.Loc) {
+	p.buildr.Call(fn, dst, args)
+	caller := p.funcs[instr.Parent()]
+	switch instr.(type) {
+	case *ssa.Go:
+		return
+	case *ssa.Defer:
+		p.buildr.Unwind(fn, caller)
+	}
+	p.buildr.Unwind(caller, fn)
 }
 
 func (p *T) putResults() {
@@ -565,5 +816,41 @@ func (p *T) putResults() {
 		p.buildr.Memory().PlainEncode(os.Stdout)
 		p.buildr.PlainEncodeObjects(os.Stdout)
 	}
+	mdl := p.buildr.Memory()
+	perm := mdl.Export([]memory.Loc{})
+	p.pkgres.TypeSet = p.buildr.TypeSet()
+	export := func(v ssa.Value) {
+		if m := perm[p.vmap[v]]; m != memory.NoLoc {
+			p.pkgres.Symbols[symbol(v)] = m
+		}
+	}
+	prog := p.pkg.Prog
+	for name, mbr := range p.pkg.Members {
+		switch mbr := mbr.(type) {
+		case *ssa.Global, *ssa.Function:
+			if token.IsExported(name) {
+				export(mbr.(ssa.Value))
+			}
+		case *ssa.Type:
+			// methods of unexported types may be called
+			// on values returned by exported functions.
+			if types.IsInterface(mbr.Type()) {
+				continue
+			}
+			for _, t := range [...]types.Type{mbr.Type(), types.NewPointer(mbr.Type())} {
+				mset := prog.MethodSets.MethodSet(t)
+				for i := 0; i < mset.Len(); i++ {
+					fn := prog.MethodValue(mset.At(i))
+					if fn != nil && fn.Pkg == p.pkg && token.IsExported(fn.Name()) {
+						export(fn)
+					}
+				}
+			}
+		}
+	}
+	for _, g := range p.spawns {
+		p.pkgres.Spawns = append(p.pkgres.Spawns, perm[g])
+	}
+	p.pkgres.MemModel = mdl
 	p.results.Put(p.pass.Pkg.Path(), p.pkgres)
 }
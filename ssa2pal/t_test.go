@@ -0,0 +1,304 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/go-air/pal/indexing"
+	"github.com/go-air/pal/memory"
+	"github.com/go-air/pal/results"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/buildssa"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// importer provides the packages translated by translate, and
+// unsafe, which has no pal results.
+type importer map[string]*types.Package
+
+func (imp importer) Import(path string) (*types.Package, error) {
+	if path == "unsafe" {
+		return types.Unsafe, nil
+	}
+	if pkg, ok := imp[path]; ok {
+		return pkg, nil
+	}
+	return nil, fmt.Errorf("no package %q", path)
+}
+
+var domains = map[string]func() indexing.T{
+	"consts":    indexing.ConstVals,
+	"intervals": indexing.Intervals,
+	"symbolic":  indexing.Symbolic}
+
+// build translates the package p with source src in the indexing
+// domain vs, after applying opts to the translation.  The memory
+// model of the result is solved.
+func build(t *testing.T, src string, vs indexing.T, opts ...func(*T)) *results.PkgRes {
+	t.Helper()
+	res, _ := results.New()
+	return translate(t, token.NewFileSet(), importer{}, res, src, vs, opts...)
+}
+
+// buildT is like build, but gives the T which translated the package.
+func buildT(t *testing.T, src string, vs indexing.T, opts ...func(*T)) *T {
+	t.Helper()
+	res, _ := results.New()
+	p := newT(t, token.NewFileSet(), importer{}, res, src, vs)
+	gen(t, p, opts...)
+	return p
+}
+
+// translate is like build, but the package with source src may
+// import those in imp, whose results are in res.  The package path
+// of the translated package is its name, and it is added to imp.
+func translate(t *testing.T, fset *token.FileSet, imp importer, res *results.T, src string, vs indexing.T, opts ...func(*T)) *results.PkgRes {
+	t.Helper()
+	p := newT(t, fset, imp, res, src, vs)
+	gen(t, p, opts...)
+	return p.pkgres
+}
+
+// newT gives the T for translating the package with source src, as
+// with translate.
+func newT(t *testing.T, fset *token.FileSet, imp importer, res *results.T, src string, vs indexing.T) *T {
+	t.Helper()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := &types.Config{Importer: imp, Sizes: types.SizesFor("gc", "amd64")}
+	name := f.Name.Name
+	pkg := types.NewPackage(name, name)
+	ssaPkg, _, err := ssautil.BuildPackage(conf, fset, pkg, []*ast.File{f}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imp[name] = pkg
+	var fns []*ssa.Function
+	for _, m := range ssaPkg.Members {
+		if fn, ok := m.(*ssa.Function); ok {
+			fns = append(fns, fn)
+		}
+	}
+	pass := &analysis.Pass{
+		Analyzer:   &analysis.Analyzer{Name: "pal", FactTypes: []analysis.Fact{res}},
+		Fset:       fset,
+		Pkg:        pkg,
+		TypesSizes: conf.Sizes,
+		ResultOf: map[*analysis.Analyzer]interface{}{
+			buildssa.Analyzer: &buildssa.SSA{Pkg: ssaPkg, SrcFuncs: fns}}}
+	p, err := New(pass, vs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// gen applies opts to p, generates its results and solves their
+// memory model.
+func gen(t *testing.T, p *T, opts ...func(*T)) {
+	t.Helper()
+	for _, opt := range opts {
+		opt(p)
+	}
+	if _, err := p.GenResult(); err != nil {
+		t.Fatal(err)
+	}
+	p.pkgres.MemModel.Solve()
+}
+
+// object gives the object of the exported global variable name.
+func object(t *testing.T, pr *results.PkgRes, name string) memory.Loc {
+	t.Helper()
+	m := pr.Lookup(name)
+	if m == memory.NoLoc {
+		t.Fatalf("no symbol %s", name)
+	}
+	objs := pr.MemModel.PointsToFor(nil, m)
+	if len(objs) != 1 {
+		t.Fatalf("%s: objects %v", name, objs)
+	}
+	return objs[0]
+}
+
+// pointees gives the locations to which the value of the exported
+// global variable name may point.
+func pointees(t *testing.T, pr *results.PkgRes, name string) []memory.Loc {
+	t.Helper()
+	return pr.MemModel.PointsToFor(nil, object(t, pr, name))
+}
+
+// mayPoint checks whether the value of the exported global variable
+// p may point to the object of the exported global variable x.
+func mayPoint(t *testing.T, pr *results.PkgRes, p, x string) bool {
+	t.Helper()
+	o := object(t, pr, x)
+	for _, m := range pointees(t, pr, p) {
+		if m == o {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGlobals(t *testing.T) {
+	src := `package p
+
+var X, Y int
+var P *int
+
+func init() { P = &X }
+`
+	for name, vs := range domains {
+		pr := build(t, src, vs())
+		if !mayPoint(t, pr, "P", "X") {
+			t.Errorf("%s: P does not point to X: %v", name, pointees(t, pr, "P"))
+		}
+		if mayPoint(t, pr, "P", "Y") {
+			t.Errorf("%s: P points to Y", name)
+		}
+	}
+}
+
+func TestSymbol(t *testing.T) {
+	src := `package a
+
+type T struct{}
+
+var X int
+
+func Get() *int { return &X }
+
+func (t *T) Get() *int { return &X }
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, _, err := ssautil.BuildPackage(&types.Config{Importer: importer{}}, fset, types.NewPackage("a", "a"), []*ast.File{f}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meth := pkg.Prog.LookupMethod(types.NewPointer(pkg.Type("T").Type()), pkg.Pkg, "Get")
+	for v, exp := range map[ssa.Value]string{
+		pkg.Var("X"):    "X",
+		pkg.Func("Get"): "Get",
+		meth:            "(*T).Get"} {
+		if got := symbol(v); got != exp {
+			t.Errorf("symbol(%s) = %s, expected %s", v, got, exp)
+		}
+	}
+}
+
+func TestImportTypes(t *testing.T) {
+	srcA := `package a
+
+var F func(int) (string, error)
+var M map[string][]float64
+`
+	srcC := `package c
+
+type L struct {
+	Next *L
+	Vals [4]int
+}
+
+var X L
+var I interface{ Get() *L }
+`
+	srcB := `package b
+
+import (
+	"a"
+	"c"
+)
+
+var P = &c.X
+var Q = a.M
+`
+	for name, vs := range domains {
+		fset := token.NewFileSet()
+		imp := importer{}
+		res, _ := results.New()
+		translate(t, fset, imp, res, srcA, vs())
+		pc := translate(t, fset, imp, res, srcC, vs())
+		pb := newT(t, fset, imp, res, srcB, vs())
+		gen(t, pb)
+		perm := pb.imports["c"]
+		mdl, ts := pb.buildr.Memory(), pb.buildr.TypeSet()
+		for i := 2; i < pc.MemModel.Len(); i++ {
+			m := memory.Loc(i)
+			cty, ty := pc.MemModel.Type(m), mdl.Type(perm[m])
+			if ts.Kind(ty) != pc.TypeSet.Kind(cty) || ts.Lsize(ty) != pc.TypeSet.Lsize(cty) {
+				t.Errorf("%s: type of c loc %d is %s, expected %s", name, m,
+					ts.Kind(ty), pc.TypeSet.Kind(cty))
+			}
+		}
+	}
+}
+
+func TestImportMethod(t *testing.T) {
+	srcA := `package a
+
+type T struct{}
+
+var X, Y int
+
+func Get() *int { return &Y }
+
+func (t *T) Get() *int { return &X }
+
+func New() *T { return &T{} }
+`
+	srcB := `package b
+
+import "a"
+
+var P *int
+
+func init() { P = a.New().Get() }
+`
+	for name, vs := range domains {
+		fset := token.NewFileSet()
+		imp := importer{}
+		res, _ := results.New()
+		pa := translate(t, fset, imp, res, srcA, vs())
+		for _, sym := range []string{"Get", "(*T).Get", "New", "X", "Y"} {
+			if pa.Lookup(sym) == memory.NoLoc {
+				t.Errorf("%s: %s not exported: %v", name, sym, pa.Symbols)
+			}
+		}
+		pb := translate(t, fset, imp, res, srcB, vs())
+		scope := imp["a"].Scope()
+		var x, y bool
+		mdl := pb.MemModel
+		for _, m := range pointees(t, pb, "P") {
+			x = x || mdl.Pos(m) == scope.Lookup("X").Pos()
+			y = y || mdl.Pos(m) == scope.Lookup("Y").Pos()
+		}
+		if !x || y {
+			t.Errorf("%s: P points to a.X %t, a.Y %t", name, x, y)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This file provides the conservative treatment of unsafe.Pointer
+// and uintptr values, see SetUnsafe.
+
+package ssa2pal
+
+import (
+	"go/types"
+
+	"github.com/go-air/pal/memory"
+	"golang.org/x/tools/go/ssa"
+)
+
+// unsafeSite represents a conversion from uintptr to unsafe.Pointer.
+type unsafeSite struct {
+	x    memory.Loc
+	dst  memory.Loc
+	done map[memory.Loc]bool
+}
+
+// SetUnsafe sets whether p models unsafe.Pointer and uintptr values
+// conservatively.  If so, uintptr values converted from pointers
+// carry the points-to sets of the pointers, converting them back
+// may result in any location of the same region, and the locations
+// which result from such conversions have the memory.IsUnsafe
+// attribute.  So do the regions they point to, and the regions
+// reachable from those, so that the locations to which values loaded
+// through such pointers point are marked, although the locations of
+// the loaded values themselves are not.  Otherwise, pointers
+// converted to uintptr are lost.  It must be called before
+// GenResult.
+func (p *T) SetUnsafe(on bool) {
+	p.unsafe = on
+}
+
+// unsafeConvert generates the loc of the conversion v to or from an
+// unsafe.Pointer and the associated constraints, returning false if
+// p does not model unsafe.Pointer values conservatively.
+func (p *T) unsafeConvert(v *ssa.Convert) (memory.Loc, bool) {
+	if !p.unsafe {
+		return memory.NoLoc, false
+	}
+	x := p.vmap[v.X]
+	res := p.buildr.FromGoType(v.Type())
+	switch {
+	case isUintptr(v.X.Type()):
+		// the result of pointer arithmetic, see resolveUnsafe.
+		p.buildr.AddTransferIndex(res, x, p.indexing.Var())
+		p.unsafeSites = append(p.unsafeSites, &unsafeSite{
+			x:    x,
+			dst:  res,
+			done: make(map[memory.Loc]bool)})
+	case isUnsafePointer(v.X.Type()):
+		p.buildr.AddTransfer(res, x)
+	default:
+		// from a pointer, which is safe.
+		p.buildr.AddTransfer(res, x)
+		return res, true
+	}
+	p.markUnsafe(res)
+	return res, true
+}
+
+// resolveUnsafe adds the locations of the regions of the points-to
+// set of the uintptr of site to the result of site, returning whether
+// there were any new ones.
+func (p *T) resolveUnsafe(site *unsafeSite) bool {
+	mdl := p.buildr.Memory()
+	res := false
+	for _, v := range mdl.PointsToFor(nil, site.x) {
+		r := mdl.Root(v)
+		if r == mdl.Zero() || site.done[r] {
+			continue
+		}
+		site.done[r] = true
+		end := r + memory.Loc(mdl.Lsize(r))
+		for m := r; m < end; m++ {
+			p.buildr.AddAddressOf(site.dst, m)
+		}
+		res = true
+	}
+	return res
+}
+
+// uintptrArith adds the constraints for the arithmetic operation
+// i9n on uintptr values, whose result carries the points-to sets of
+// its operands.
+func (p *T) uintptrArith(i9n *ssa.BinOp) {
+	if !p.unsafe {
+		return
+	}
+	res := p.vmap[i9n]
+	for _, x := range [...]ssa.Value{i9n.X, i9n.Y} {
+		if m := p.vmap[x]; m != memory.NoLoc {
+			p.buildr.AddTransfer(res, m)
+		}
+	}
+	p.markUnsafe(res)
+}
+
+// markUnsafe marks m with memory.IsUnsafe and records it so that
+// the locations it points to are marked once solved, see
+// markUnsafePointees.
+func (p *T) markUnsafe(m memory.Loc) {
+	p.buildr.Memory().AddAttrs(m, memory.IsUnsafe)
+	p.unsafes = append(p.unsafes, m)
+}
+
+// markUnsafePointees marks the regions pointed to by the locs
+// recorded by markUnsafe, and the regions reachable from them, with
+// memory.IsUnsafe.
+func (p *T) markUnsafePointees() {
+	mdl := p.buildr.Memory()
+	seen := make(map[memory.Loc]bool)
+	mark := func(r memory.Loc) {
+		if seen[r] {
+			return
+		}
+		seen[r] = true
+		end := r + memory.Loc(mdl.Lsize(r))
+		for m := r; m < end; m++ {
+			mdl.AddAttrs(m, memory.IsUnsafe)
+		}
+	}
+	var pts, reach []memory.Loc
+	for _, m := range p.unsafes {
+		pts = mdl.PointsToFor(pts[:0], m)
+		for _, o := range pts {
+			r := mdl.Root(o)
+			if r == mdl.Zero() || seen[r] {
+				continue
+			}
+			mark(r)
+			reach = mdl.Reachable(reach[:0], r)
+			for _, q := range reach {
+				mark(q)
+			}
+		}
+	}
+}
+
+func isUintptr(ty types.Type) bool {
+	b, ok := ty.Underlying().(*types.Basic)
+	return ok && b.Kind() == types.Uintptr
+}
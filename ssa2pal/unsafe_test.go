@@ -0,0 +1,55 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssa2pal
+
+import "testing"
+
+func TestUnsafe(t *testing.T) {
+	src := `package p
+
+import "unsafe"
+
+type S struct{ a, b **int }
+
+var X, Y, Z int
+var PX, PY *int
+var Q, R *int
+
+func init() {
+	PX, PY = &X, &Y
+	s := &S{a: &PX, b: &PY}
+	u := uintptr(unsafe.Pointer(s)) + unsafe.Offsetof(s.b)
+	q := (***int)(unsafe.Pointer(u))
+	Q = **q
+	R = &Z
+}
+`
+	for name, vs := range domains {
+		for _, on := range []bool{false, true} {
+			pr := build(t, src, vs(), func(p *T) { p.SetUnsafe(on) })
+			mdl := pr.MemModel
+			if on && !mayPoint(t, pr, "Q", "Y") {
+				t.Errorf("%s: Q -> %v", name, pointees(t, pr, "Q"))
+			}
+			// X and Y are reachable from the pointer converted
+			// from u, Z is not.
+			for v, exp := range map[string]bool{"X": on, "Y": on, "Z": false} {
+				if got := mdl.Attrs(object(t, pr, v)).IsUnsafe(); got != exp {
+					t.Errorf("%s unsafe=%t: %s unsafe %t", name, on, v, got)
+				}
+			}
+		}
+	}
+}
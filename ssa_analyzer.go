@@ -29,6 +29,9 @@ import (
 
 var flagSet = flag.NewFlagSet("pal", flag.ExitOnError)
 var palVersion = flagSet.Bool("V", false, "print out pal version")
+var palIndexing = flagSet.String("indexing", "consts", "indexing domain: consts, intervals, or symbolic")
+var palRefine = flagSet.Bool("refine", false, "refine indices with branch conditions")
+var palUnsafe = flagSet.Bool("unsafe", false, "model unsafe.Pointer and uintptr conservatively")
 
 type resultType int
 
@@ -63,9 +66,22 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		fmt.Printf("%s\n", v)
 		os.Exit(0)
 	}
-	pal, err := ssa2pal.New(pass, indexing.ConstVals())
+	var idx indexing.T
+	switch *palIndexing {
+	case "consts":
+		idx = indexing.ConstVals()
+	case "intervals":
+		idx = indexing.Intervals()
+	case "symbolic":
+		idx = indexing.SymbolicIn(pass.Pkg.Path())
+	default:
+		return nil, fmt.Errorf("unknown indexing domain %q", *palIndexing)
+	}
+	pal, err := ssa2pal.New(pass, idx)
 	if err != nil {
 		return nil, err
 	}
+	pal.SetRefine(*palRefine)
+	pal.SetUnsafe(*palUnsafe)
 	return pal.GenResult()
 }
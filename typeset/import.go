@@ -0,0 +1,86 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeset
+
+// Import adds the types of other to t, returning their relocation:
+// the type ty of other is the type res[ty] of t.  Basic types are
+// the same in every TypeSet, and named types are identified by name.
+func (t *TypeSet) Import(other *TypeSet) []Type {
+	res := make([]Type, len(other.nodes))
+	for i := Type(0); i < _endType; i++ {
+		res[i] = i
+	}
+	for i := range other.nodes {
+		t.importType(other, Type(i), res)
+	}
+	return res
+}
+
+func (t *TypeSet) importType(other *TypeSet, ty Type, perm []Type) Type {
+	if ty < _endType || perm[ty] != NoType {
+		return perm[ty]
+	}
+	onode := &other.nodes[ty]
+	var res Type
+	switch onode.kind {
+	case Named:
+		// set perm[ty] first, as the underlying type may
+		// refer to ty.
+		var creat bool
+		res, creat = t.getNamed(onode.fields[0].name)
+		perm[ty] = res
+		if creat {
+			under := t.importType(other, onode.elem, perm)
+			t.nodes[res].elem = under
+			t.nodes[res].lsize = t.Lsize(under)
+		}
+		return res
+	case Pointer:
+		res = t.getPointer(t.importType(other, onode.elem, perm))
+	case Slice:
+		res = t.getSlice(t.importType(other, onode.elem, perm))
+	case Chan:
+		res = t.getChan(t.importType(other, onode.elem, perm))
+	case Array:
+		res = t.getArray(t.importType(other, onode.elem, perm), other.ArrayLen(ty))
+	case Map:
+		kty := t.importType(other, onode.key, perm)
+		res = t.getMap(kty, t.importType(other, onode.elem, perm))
+	case Struct:
+		res = t.getStruct(t.importNameds(other, onode.fields, perm))
+	case Interface:
+		res = t.getInterface(t.importNameds(other, onode.fields, perm))
+	case Tuple:
+		res = t.getTuple(t.importNameds(other, onode.fields, perm))
+	case Func:
+		recv := t.importType(other, onode.key, perm)
+		params := t.importNameds(other, onode.params, perm)
+		results := t.importNameds(other, onode.results, perm)
+		res = t.getSignature(recv, params, results, onode.variadic)
+	default:
+		return NoType
+	}
+	perm[ty] = res
+	return res
+}
+
+func (t *TypeSet) importNameds(other *TypeSet, elts []named, perm []Type) []named {
+	res := make([]named, len(elts))
+	for i, elt := range elts {
+		res[i] = elt
+		res[i].typ = t.importType(other, elt.typ, perm)
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+// Copyright 2021 The pal authors (see AUTHORS)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeset
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+func TestTypeSetImport(t *testing.T) {
+	// type List struct { next *List; vals []float64; f func(int) (map[string]*List, error) }
+	pkg := types.NewPackage("a", "a")
+	tn := types.NewTypeName(token.NoPos, pkg, "List", nil)
+	list := types.NewNamed(tn, nil, nil)
+	errTy := types.Universe.Lookup("error").Type()
+	sig := types.NewSignature(nil,
+		types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Typ[types.Int])),
+		types.NewTuple(
+			types.NewVar(token.NoPos, nil, "", types.NewMap(types.Typ[types.String], types.NewPointer(list))),
+			types.NewVar(token.NoPos, nil, "", errTy)),
+		false)
+	list.SetUnderlying(types.NewStruct([]*types.Var{
+		types.NewField(token.NoPos, pkg, "next", types.NewPointer(list), false),
+		types.NewField(token.NoPos, pkg, "vals", types.NewSlice(types.Typ[types.Float64]), false),
+		types.NewField(token.NoPos, pkg, "f", sig, false)}, nil))
+	gts := []types.Type{list, types.NewPointer(list), types.NewArray(list, 3), sig}
+
+	other := New()
+	for _, gt := range gts {
+		other.FromGoType(gt)
+	}
+	ts := New()
+	ts.FromGoType(types.NewSlice(types.Typ[types.Int]))
+	ts.FromGoType(types.NewPointer(list))
+	perm := ts.Import(other)
+	n := ts.Len()
+	for _, gt := range gts {
+		ty := perm[other.FromGoType(gt)]
+		if ts.FromGoType(gt) != ty {
+			t.Errorf("%s not relocated", gt)
+		}
+		if ts.Lsize(ty) != other.Lsize(other.FromGoType(gt)) {
+			t.Errorf("%s: lsize %d != %d", gt, ts.Lsize(ty), other.Lsize(other.FromGoType(gt)))
+		}
+	}
+	if ts.Len() != n {
+		t.Errorf("imported types not shared, %d != %d", ts.Len(), n)
+	}
+}
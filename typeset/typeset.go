@@ -202,7 +202,11 @@ func (t *TypeSet) getInterface(meths []named) Type {
 func (t *TypeSet) getSignature(recv Type, params, results []named, variadic bool) Type {
 	ty, node := t.newNode()
 	node.kind = Func
+	node.key = recv
 	node.lsize = 1 + len(params) + len(results)
+	if recv != NoType {
+		node.lsize++
+	}
 	node.params = params
 	node.results = results
 	node.variadic = variadic
@@ -246,7 +250,7 @@ func (t *TypeSet) getOrMake(ty Type, node *node) Type {
 		}
 		ni = t.nodes[ni].next
 	}
-	node.next = t.hash[ty]
+	node.next = t.hash[ci]
 	t.hash[ci] = ty
 	return ty
 }